@@ -0,0 +1,54 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+	"go.uber.org/zap"
+)
+
+// Get 从全局配置读取 key 并转换为 def 的类型，配置项不存在时返回 def。
+//
+// 支持 string、int、int64、float64、bool、time.Duration、[]string、[]int，
+// 其他类型要求配置值本身就是该类型。值无法转换时记录一条警告并返回 def。
+func Get[T any](key string, def T) T {
+	if Config.V == nil || !Config.V.IsSet(key) {
+		return def
+	}
+	raw := Config.V.Get(key)
+
+	var (
+		val interface{}
+		err error
+	)
+	switch any(def).(type) {
+	case string:
+		val, err = cast.ToStringE(raw)
+	case int:
+		val, err = cast.ToIntE(raw)
+	case int64:
+		val, err = cast.ToInt64E(raw)
+	case float64:
+		val, err = cast.ToFloat64E(raw)
+	case bool:
+		val, err = cast.ToBoolE(raw)
+	case time.Duration:
+		val, err = cast.ToDurationE(raw)
+	case []string:
+		val, err = cast.ToStringSliceE(raw)
+	case []int:
+		val, err = cast.ToIntSliceE(raw)
+	default:
+		val = raw
+	}
+	if err != nil {
+		zap.L().Warn("config value cannot be converted, using default", zap.String("key", key), zap.Any("value", raw), zap.Any("default", def), zap.Error(err))
+		return def
+	}
+	v, ok := val.(T)
+	if !ok {
+		zap.L().Warn("config value has unexpected type, using default", zap.String("key", key), zap.Any("value", raw), zap.Any("default", def))
+		return def
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate 按 spec 声明的结构校验已加载的全局配置，见 ViperConfig.Validate
+func Validate(spec interface{}) error {
+	return Config.Validate(spec)
+}
+
+// Validate 按 spec 声明的结构校验配置，所有问题一次性返回。
+//
+// spec 是结构体（或其指针），配置项名称取 mapstructure 标签，没有则取小写字段名，
+// 嵌套结构体对应嵌套的配置项，map 类型字段下的子项不做限制。
+// 约束写在 validate 标签中，多个约束用逗号分隔：
+//
+//	required        配置项必须存在
+//	min=1,max=100   数值的取值范围；字符串、切片为长度范围；time.Duration 写成 min=1s
+//	oneof=a b c     取值必须是其中之一
+//
+// 配置文件中出现 spec 未声明的配置项同样视为错误。
+func (vc *ViperConfig) Validate(spec interface{}) error {
+	if vc.V == nil {
+		return errors.New("配置尚未加载")
+	}
+	t := reflect.TypeOf(spec)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("spec 必须是结构体，当前为 %v", reflect.TypeOf(spec))
+	}
+
+	declared := map[string]bool{}
+	open := map[string]bool{}
+	errs := vc.validateStruct(t, "", declared, open)
+
+	keys := vc.V.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if declared[key] || underOpen(key, open) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%v: 未知的配置项", key))
+	}
+	return errors.Join(errs...)
+}
+
+func (vc *ViperConfig) validateStruct(t reflect.Type, prefix string, declared, open map[string]bool) []error {
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := prefix + strings.ToLower(name)
+		declared[key] = true
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Map {
+			open[key] = true
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			if tagRule(field, "required") != nil && !vc.V.IsSet(key) {
+				errs = append(errs, fmt.Errorf("%v: 缺少必填配置项", key))
+			}
+			errs = append(errs, vc.validateStruct(ft, key+".", declared, open)...)
+			continue
+		}
+		errs = append(errs, vc.validateField(key, ft, field)...)
+	}
+	return errs
+}
+
+func (vc *ViperConfig) validateField(key string, ft reflect.Type, field reflect.StructField) []error {
+	var errs []error
+	if !vc.V.IsSet(key) {
+		if tagRule(field, "required") != nil {
+			errs = append(errs, fmt.Errorf("%v: 缺少必填配置项", key))
+		}
+		return errs
+	}
+	for _, rule := range []string{"min", "max"} {
+		limit := tagRule(field, rule)
+		if limit == nil {
+			continue
+		}
+		val, bound, err := vc.measure(key, ft, *limit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %v", key, err))
+			continue
+		}
+		if rule == "min" && val < bound {
+			errs = append(errs, fmt.Errorf("%v: 值 %v 小于最小值 %v", key, vc.V.Get(key), *limit))
+		}
+		if rule == "max" && val > bound {
+			errs = append(errs, fmt.Errorf("%v: 值 %v 大于最大值 %v", key, vc.V.Get(key), *limit))
+		}
+	}
+	if options := tagRule(field, "oneof"); options != nil {
+		val := vc.V.GetString(key)
+		found := false
+		for _, opt := range strings.Fields(*options) {
+			if opt == val {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%v: 值 %v 不在可选范围 [%v] 内", key, val, *options))
+		}
+	}
+	return errs
+}
+
+// measure 返回配置项用于范围比较的量值，以及约束值换算后的结果
+func (vc *ViperConfig) measure(key string, ft reflect.Type, limit string) (float64, float64, error) {
+	if ft == reflect.TypeOf(time.Duration(0)) {
+		bound, err := time.ParseDuration(limit)
+		if err != nil {
+			return 0, 0, fmt.Errorf("约束值 %v 不是合法的时间间隔", limit)
+		}
+		return float64(vc.V.GetDuration(key)), float64(bound), nil
+	}
+	bound, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("约束值 %v 不是合法的数字", limit)
+	}
+	switch ft.Kind() {
+	case reflect.String:
+		return float64(len(vc.V.GetString(key))), bound, nil
+	case reflect.Slice, reflect.Array:
+		return float64(len(vc.V.GetStringSlice(key))), bound, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(vc.V.GetString(key), 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("值 %v 不是合法的数字", vc.V.Get(key))
+		}
+		return val, bound, nil
+	}
+	return 0, 0, fmt.Errorf("类型 %v 不支持 min/max 约束", ft)
+}
+
+// tagRule 返回 validate 标签中指定约束的参数，约束不存在时返回 nil
+func tagRule(field reflect.StructField, name string) *string {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == name {
+			empty := ""
+			return &empty
+		}
+		if strings.HasPrefix(rule, name+"=") {
+			arg := rule[len(name)+1:]
+			return &arg
+		}
+	}
+	return nil
+}
+
+// underOpen 判断配置项是否位于 map 类型的字段之下
+func underOpen(key string, open map[string]bool) bool {
+	for prefix := range open {
+		if strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// init 会解析命令行并加载配置文件，这里先注册测试参数并指定测试用的配置文件
+var _ = func() bool {
+	testing.Init()
+	os.Args = append(os.Args, "-conf", "testdata/config.yaml")
+	return true
+}()
+
+type validateSpec struct {
+	Name    string        `validate:"required,oneof=dev test prod"`
+	Port    int           `validate:"min=1,max=65535"`
+	Timeout time.Duration `validate:"min=1s"`
+	Hosts   []string      `validate:"min=1"`
+	DB      struct {
+		DSN string `mapstructure:"dsn" validate:"required"`
+	} `validate:"required"`
+	Labels map[string]string
+}
+
+func loadYAML(t *testing.T, content string) *ViperConfig {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatalf("读取配置失败: %v", err)
+	}
+	return &ViperConfig{V: v}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		yaml string
+		errs []string
+	}{
+		{
+			name: "合法配置",
+			yaml: "name: dev\nport: 8080\ntimeout: 3s\nhosts: [a]\ndb:\n  dsn: x\nlabels:\n  any: v\n",
+		},
+		{
+			name: "缺少必填项",
+			yaml: "port: 8080\n",
+			errs: []string{"name: 缺少必填配置项", "db: 缺少必填配置项", "db.dsn: 缺少必填配置项"},
+		},
+		{
+			name: "超出范围",
+			yaml: "name: dev\nport: 70000\ntimeout: 10ms\nhosts: []\ndb:\n  dsn: x\n",
+			errs: []string{"port: 值 70000 大于最大值 65535", "timeout: 值 10ms 小于最小值 1s", "hosts: 值 [] 小于最小值 1"},
+		},
+		{
+			name: "不在可选范围",
+			yaml: "name: staging\ndb:\n  dsn: x\n",
+			errs: []string{"name: 值 staging 不在可选范围 [dev test prod] 内"},
+		},
+		{
+			name: "未知配置项",
+			yaml: "name: dev\ndb:\n  dsn: x\n  user: root\nextra: 1\n",
+			errs: []string{"db.user: 未知的配置项", "extra: 未知的配置项"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := loadYAML(t, c.yaml).Validate(validateSpec{})
+			if len(c.errs) == 0 {
+				if err != nil {
+					t.Fatalf("期望校验通过，得到 %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("期望校验失败，得到 nil")
+			}
+			for _, want := range c.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("错误信息 %q 中缺少 %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	vc := loadYAML(t, "name: dev\n")
+	if err := vc.Validate(1); err == nil {
+		t.Errorf("spec 不是结构体时应返回错误")
+	}
+	if err := (&ViperConfig{}).Validate(validateSpec{}); err == nil {
+		t.Errorf("配置未加载时应返回错误")
+	}
+}
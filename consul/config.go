@@ -12,6 +12,9 @@ type Info struct {
 	CheckInterval                  string   `json:"checkInterval"`                  // 健康检查间隔
 	DeregisterCriticalServiceAfter string   `json:"deregisterCriticalServiceAfter"` // check失败后30秒删除本服务，注销时间，相当于过期时间
 	GRPC                                    // grpc 支持，执行健康检查的地址，service 会传到 Health.Check 函数中
+
+	Meta  map[string]string `json:"meta"` // 实例元数据，可以为空，注册后可通过 Registration().UpdateMeta 修改
+	Ready func() error      `json:"-"`    // 预热检查，不为空时先等待其返回 nil 再注册，返回 error 则放弃注册
 }
 type ClientInfo struct {
 	Name    string `json:"name"`    // 服务名称
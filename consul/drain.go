@@ -0,0 +1,57 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	consulApi "github.com/hashicorp/consul/api"
+)
+
+// Registration 已注册实例的句柄，Drain、UpdateMeta、SetTags 通过它找到实例所在的 agent
+type Registration struct {
+	ID            string // 服务节点的名称
+	ConsulAddress string // consul agent 地址，为空时使用 consul 默认配置（CONSUL_HTTP_ADDR 或 127.0.0.1:8500）
+}
+
+// Registration 返回 info 对应实例的句柄
+func (info *Info) Registration() *Registration {
+	return &Registration{ID: info.ID, ConsulAddress: info.ConsulAddress}
+}
+
+// client 创建连接实例所在 agent 的客户端
+func (r *Registration) client() (*consulApi.Client, error) {
+	if r.ID == "" {
+		return nil, errors.New("consul service id error : 服务节点名称不能为空")
+	}
+	config := consulApi.DefaultConfig()
+	if r.ConsulAddress != "" {
+		config.Address = r.ConsulAddress
+	}
+	client, err := consulApi.NewClient(config)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("consul client error : %v", err.Error()))
+	}
+	return client, nil
+}
+
+// Drain 平滑下线实例：先进入维护模式，等待 delay 让客户端刷新缓存、停止选择该实例，再注销。
+// 维护模式下实例的健康检查为 critical，SearchServer 只返回 passing 的实例，因此会排除它。
+// 应当在关闭服务监听之前调用，等待期间服务仍可正常处理请求。
+func (r *Registration) Drain(delay time.Duration, reason string) error {
+	client, err := r.client()
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		reason = "draining"
+	}
+	if err = client.Agent().EnableServiceMaintenance(r.ID, reason); err != nil {
+		return errors.New(fmt.Sprintf("consul maintenance %v error : %v", r.ID, err.Error()))
+	}
+	time.Sleep(delay)
+	if err = client.Agent().ServiceDeregister(r.ID); err != nil {
+		return errors.New(fmt.Sprintf("deregister server %v error : %v", r.ID, err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package consul
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	consulApi "github.com/hashicorp/consul/api"
+)
+
+// fakeAgent 模拟 consul agent 的 HTTP 接口，记录收到的请求
+type fakeAgent struct {
+	*httptest.Server
+	mu       sync.Mutex
+	calls    []string
+	times    []time.Time
+	service  consulApi.AgentService
+	register consulApi.AgentServiceRegistration
+}
+
+func newFakeAgent(t *testing.T) *fakeAgent {
+	a := &fakeAgent{service: consulApi.AgentService{ID: "s1", Service: "svc", Port: 80, Meta: map[string]string{"a": "1", "b": "2"}}}
+	a.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.calls = append(a.calls, r.Method+" "+r.URL.Path)
+		a.times = append(a.times, time.Now())
+		switch r.URL.Path {
+		case "/v1/agent/service/s1":
+			_ = json.NewEncoder(w).Encode(a.service)
+		case "/v1/agent/service/register":
+			_ = json.NewDecoder(r.Body).Decode(&a.register)
+		}
+	}))
+	t.Cleanup(a.Close)
+	return a
+}
+
+func (a *fakeAgent) Calls() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.calls...)
+}
+
+func TestDrain(t *testing.T) {
+	agent := newFakeAgent(t)
+	r := &Registration{ID: "s1", ConsulAddress: agent.URL}
+	delay := 50 * time.Millisecond
+	if err := r.Drain(delay, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PUT /v1/agent/service/maintenance/s1", "PUT /v1/agent/service/deregister/s1"}
+	if calls := agent.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if gap := agent.times[1].Sub(agent.times[0]); gap < delay {
+		t.Errorf("deregistered %v after maintenance, want at least %v", gap, delay)
+	}
+
+	if err := (&Registration{ConsulAddress: agent.URL}).Drain(0, ""); err == nil {
+		t.Errorf("Drain without ID should fail")
+	}
+}
+
+func TestUpdateMeta(t *testing.T) {
+	agent := newFakeAgent(t)
+	r := (&Info{ID: "s1", ConsulAddress: agent.URL}).Registration()
+	if err := r.UpdateMeta(map[string]string{"a": "", "c": "draining"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"GET /v1/agent/service/s1", "PUT /v1/agent/service/register"}
+	if calls := agent.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	reg := agent.register
+	if reg.ID != "s1" || reg.Name != "svc" || reg.Port != 80 || !reflect.DeepEqual(reg.Meta, map[string]string{"b": "2", "c": "draining"}) {
+		t.Errorf("registration = %+v", reg)
+	}
+}
+
+func TestRegisterServerReady(t *testing.T) {
+	agent := newFakeAgent(t)
+	info := &Info{ID: "s1", Name: "svc", Address: "127.0.0.1", ConsulAddress: agent.URL, CheckPort: 1}
+
+	// 预热失败时放弃注册
+	info.Ready = func() error { return errors.New("cache not loaded") }
+	if err := RegisterServer(info); err == nil || !strings.Contains(err.Error(), "consul warmup error") {
+		t.Fatalf("RegisterServer = %v, want warmup error", err)
+	}
+	if calls := agent.Calls(); len(calls) != 0 {
+		t.Fatalf("calls = %v, want none", calls)
+	}
+
+	// 预热完成后才注册；健康检查端口已被占用，注册后 ListenAndServe 立即返回
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	info.CheckPort = l.Addr().(*net.TCPAddr).Port
+	var readyCalls int
+	info.Ready = func() error {
+		readyCalls++
+		if calls := agent.Calls(); len(calls) != 0 {
+			t.Errorf("calls before ready = %v", calls)
+		}
+		return nil
+	}
+	if err := RegisterServer(info); err == nil {
+		t.Fatalf("RegisterServer on a used port should fail")
+	}
+	if want := []string{"PUT /v1/agent/service/register"}; readyCalls != 1 || !reflect.DeepEqual(agent.Calls(), want) {
+		t.Errorf("ready calls = %d, agent calls = %v, want 1, %v", readyCalls, agent.Calls(), want)
+	}
+}
@@ -0,0 +1,65 @@
+package consul
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	consulApi "github.com/hashicorp/consul/api"
+)
+
+// ServiceConfigPrefix 服务运行时配置在 KV 中的前缀，%v 为服务名称
+var ServiceConfigPrefix = "service/%v/config/"
+
+// ServiceConfig 读取服务在 KV 中的配置，键去掉前缀后返回，子目录以 / 分隔保留在键中。
+// 前缀下的全部键由一次 List 请求读取，结果是同一时刻的快照
+func ServiceConfig(info *ClientInfo, service string) (map[string]string, error) {
+	if err := CheckIPAddr(info.Address); err != nil {
+		return nil, err
+	}
+	config := consulApi.DefaultConfig()
+	config.Address = info.Address
+	client, err := consulApi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("api new client is failed, error: %v", err)
+	}
+
+	prefix := fmt.Sprintf(ServiceConfigPrefix, service)
+	pairs, _, err := client.KV().List(prefix, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing kv %v from Consul, error: %v", prefix, err)
+	}
+	settings := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key := strings.TrimPrefix(pair.Key, prefix)
+		// 以 / 结尾的是目录占位
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue
+		}
+		settings[key] = string(pair.Value)
+	}
+	return settings, nil
+}
+
+// SearchServerWithConfig 并发获取服务地址（同 SearchServer）和 KV 中的配置（同 ServiceConfig）
+func SearchServerWithConfig(info *ClientInfo) (addrs map[string]string, settings map[string]string, err error) {
+	var wg sync.WaitGroup
+	var searchErr, configErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		addrs, searchErr = SearchServer(info)
+	}()
+	go func() {
+		defer wg.Done()
+		settings, configErr = ServiceConfig(info, info.Name)
+	}()
+	wg.Wait()
+	if searchErr != nil {
+		return nil, nil, searchErr
+	}
+	if configErr != nil {
+		return nil, nil, configErr
+	}
+	return addrs, settings, nil
+}
@@ -0,0 +1,69 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+
+	consulApi "github.com/hashicorp/consul/api"
+)
+
+// UpdateMeta 修改已注册实例的元数据，meta 中的 key 合并到现有元数据，值为空字符串时删除该 key。
+// 例如下线前标记 draining，调用方不需要重新提供完整的注册信息。
+func (r *Registration) UpdateMeta(meta map[string]string) error {
+	return r.patch(func(reg *consulApi.AgentServiceRegistration) {
+		if reg.Meta == nil {
+			reg.Meta = map[string]string{}
+		}
+		for k, v := range meta {
+			if v == "" {
+				delete(reg.Meta, k)
+				continue
+			}
+			reg.Meta[k] = v
+		}
+	})
+}
+
+// SetTags 替换已注册实例的 tag
+func (r *Registration) SetTags(tags []string) error {
+	return r.patch(func(reg *consulApi.AgentServiceRegistration) {
+		reg.Tags = tags
+	})
+}
+
+// patch 读取 agent 上的实例定义，修改后重新提交，已有的健康检查保持不变
+func (r *Registration) patch(patch func(reg *consulApi.AgentServiceRegistration)) error {
+	client, err := r.client()
+	if err != nil {
+		return err
+	}
+	service, _, err := client.Agent().Service(r.ID, nil)
+	if err != nil {
+		return errors.New(fmt.Sprintf("consul service %v error : %v", r.ID, err.Error()))
+	}
+
+	registration := &consulApi.AgentServiceRegistration{
+		Kind:              service.Kind,
+		ID:                service.ID,
+		Name:              service.Service,
+		Tags:              service.Tags,
+		Port:              service.Port,
+		Address:           service.Address,
+		TaggedAddresses:   service.TaggedAddresses,
+		EnableTagOverride: service.EnableTagOverride,
+		Meta:              service.Meta,
+		Weights:           &service.Weights,
+		Proxy:             service.Proxy,
+		Connect:           service.Connect,
+		Namespace:         service.Namespace,
+		Partition:         service.Partition,
+	}
+	patch(registration)
+
+	// 不替换已有的健康检查
+	err = client.Agent().ServiceRegisterOpts(registration, consulApi.ServiceRegisterOpts{ReplaceExistingChecks: false})
+	if err != nil {
+		return errors.New(fmt.Sprintf("update server %v error : %v", r.ID, err.Error()))
+	}
+	return nil
+}
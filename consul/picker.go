@@ -0,0 +1,114 @@
+package consul
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	consulApi "github.com/hashicorp/consul/api"
+)
+
+// WeightMetaKey 实例元数据中表示权重的键
+const WeightMetaKey = "weight"
+
+// Picker 按实例元数据中的 weight 做加权轮询（平滑加权轮询，与 nginx 相同），
+// 没有 weight 或 weight 不合法的实例权重为 1
+type Picker struct {
+	info      *ClientInfo
+	mu        sync.Mutex
+	instances []*weighted
+}
+
+type weighted struct {
+	addr    string
+	weight  int
+	current int
+}
+
+// NewPicker 创建一个 Picker 并拉取一次健康实例
+func NewPicker(info *ClientInfo) (*Picker, error) {
+	p := &Picker{info: info}
+	if err := p.Refresh(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Refresh 重新拉取健康实例，地址不变的实例保留当前轮询状态
+func (p *Picker) Refresh() error {
+	if err := CheckIPAddr(p.info.Address); err != nil {
+		return err
+	}
+	config := consulApi.DefaultConfig()
+	config.Address = p.info.Address
+	client, err := consulApi.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("api new client is failed, error: %v", err)
+	}
+	services, _, err := client.Health().Service(p.info.Name, p.info.Tag, true, nil)
+	if err != nil {
+		return fmt.Errorf("retrieving instances from Consul, error: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := map[string]*weighted{}
+	for _, w := range p.instances {
+		old[w.addr] = w
+	}
+	instances := make([]*weighted, 0, len(services))
+	for _, service := range services {
+		addr := net.JoinHostPort(service.Service.Address, strconv.Itoa(service.Service.Port))
+		weight := parseWeight(service.Service.Meta)
+		if w, ok := old[addr]; ok && w.weight == weight {
+			instances = append(instances, w)
+			continue
+		}
+		instances = append(instances, &weighted{addr: addr, weight: weight})
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].addr < instances[j].addr })
+	p.instances = instances
+	return nil
+}
+
+// Next 返回下一个实例的地址（host:port）
+func (p *Picker) Next() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.instances) == 0 {
+		return "", errors.New(fmt.Sprintf("no healthy instance of %v", p.info.Name))
+	}
+	total := 0
+	var best *weighted
+	for _, w := range p.instances {
+		w.current += w.weight
+		total += w.weight
+		if best == nil || w.current > best.current {
+			best = w
+		}
+	}
+	best.current -= total
+	return best.addr, nil
+}
+
+// Weights 返回各实例生效的权重，用于排查
+func (p *Picker) Weights() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	weights := make(map[string]int, len(p.instances))
+	for _, w := range p.instances {
+		weights[w.addr] = w.weight
+	}
+	return weights
+}
+
+func parseWeight(meta map[string]string) int {
+	weight, err := strconv.Atoi(meta[WeightMetaKey])
+	if err != nil || weight <= 0 {
+		return 1
+	}
+	return weight
+}
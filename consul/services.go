@@ -29,6 +29,12 @@ func RegisterServer(info *Info) error {
 	if info.DeregisterCriticalServiceAfter == "" {
 		info.DeregisterCriticalServiceAfter = "30s"
 	}
+	// 预热完成前不注册，避免流量提前进来
+	if info.Ready != nil {
+		if err = info.Ready(); err != nil {
+			return errors.New(fmt.Sprintf("consul warmup error : %v", err.Error()))
+		}
+	}
 	config := consulApi.DefaultConfig()
 	config.Address = info.ConsulAddress
 	client, err := consulApi.NewClient(config)
@@ -41,6 +47,7 @@ func RegisterServer(info *Info) error {
 	registration.Port = info.Port       // 服务端口
 	registration.Tags = info.Tags       // tag，可以为空
 	registration.Address = info.Address // 服务 IP
+	registration.Meta = info.Meta       // 实例元数据
 
 	checkPort := info.CheckPort
 	registration.Check = &consulApi.AgentServiceCheck{ // 健康检查
@@ -0,0 +1,131 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CorsConfig 跨域配置，gin 中间件 ECorsPlus 和 net/http 中间件 HTTP 共用
+type CorsConfig struct {
+	AllowOrigins     []string      // 允许的来源，"*" 表示全部，支持 "https://*.example.com" 形式的通配
+	AllowMethods     []string      // 默认 GET、POST、PUT、PATCH、DELETE、OPTIONS
+	AllowHeaders     []string      // 为空时原样允许预检请求中声明的请求头
+	ExposeHeaders    []string      // 允许前端读取的响应头
+	AllowCredentials bool          // 是否允许携带凭证，开启后不会返回 "*"，而是回显请求的 Origin
+	MaxAge           time.Duration // 预检结果的缓存时间，0 表示不设置
+	Stats            *OriginStats  // 按来源统计允许/拒绝的请求数，为 nil 时不统计
+}
+
+// DefaultCorsConfig 与 ECors 行为一致的配置
+func DefaultCorsConfig() CorsConfig {
+	return CorsConfig{
+		AllowOrigins:     []string{"*"},
+		AllowMethods:     []string{"POST", "GET", "OPTIONS", "PUT", "DELETE", "PATCH"},
+		AllowHeaders:     []string{"Content-Type", "AccessToken", "X-CSRF-Token", "Authorization", "Token"},
+		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
+		AllowCredentials: true,
+	}
+}
+
+// ECorsPlus 按配置处理跨域请求的 gin 中间件
+func ECorsPlus(cfg CorsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.apply(c.Writer.Header(), c.Request) {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// HTTP 按配置处理跨域请求的 net/http 中间件，行为与 ECorsPlus 一致
+func HTTP(cfg CorsConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.apply(w.Header(), r) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apply 写入跨域响应头，返回 true 表示是预检请求，应直接以 204 结束。
+// 没有 Origin 或来源不被允许的请求不写跨域头，交给浏览器拦截
+func (cfg CorsConfig) apply(h http.Header, r *http.Request) bool {
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return preflight
+	}
+	allowed := cfg.allowOrigin(origin)
+	if cfg.Stats != nil {
+		cfg.Stats.record(origin, allowed)
+	}
+	if !allowed {
+		return preflight
+	}
+
+	h.Add("Vary", "Origin")
+	if cfg.allowAll() && !cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(cfg.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
+	}
+	if !preflight {
+		return false
+	}
+
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(cfg.AllowHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowHeaders, ", "))
+	} else if req := r.Header.Get("Access-Control-Request-Headers"); req != "" {
+		h.Set("Access-Control-Allow-Headers", req)
+	}
+	if cfg.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge/time.Second)))
+	}
+	return true
+}
+
+func (cfg CorsConfig) allowAll() bool {
+	for _, o := range cfg.AllowOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg CorsConfig) allowOrigin(origin string) bool {
+	for _, o := range cfg.AllowOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+		// https://*.example.com 匹配任意子域名
+		if i := strings.Index(o, "*"); i >= 0 {
+			prefix, suffix := o[:i], o[i+1:]
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package cors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// OverflowOrigin 超出 MaxOrigins 后被淘汰的来源计入的汇总项
+const OverflowOrigin = "other"
+
+// OriginCount 一个来源的请求数
+type OriginCount struct {
+	Allowed uint64 `json:"allowed"`
+	Denied  uint64 `json:"denied"`
+}
+
+// OriginStats 按来源统计跨域请求数，设置到 CorsConfig.Stats 后生效，零值可以直接使用。
+// 最多单独记录 MaxOrigins 个来源，内存占用有上限：记满后出现新来源时，淘汰请求数最少的来源，
+// 其计数并入 OverflowOrigin。淘汰按 Space-Saving 的方式排序，新来源继承被淘汰来源的请求数作为排序基数，
+// 因此请求足够多的来源不会被大量偶发的来源挤掉，保留的是请求数最多的来源；
+// 各来源的计数是下限，被淘汰过的来源之前的请求计入了 OverflowOrigin
+type OriginStats struct {
+	MaxOrigins int // <= 0 时取 100
+
+	mu     sync.Mutex
+	counts map[string]*originEntry
+	other  OriginCount
+}
+
+type originEntry struct {
+	OriginCount
+	base uint64 // 排序基数，取自被它替换的来源的请求数
+}
+
+func (e *originEntry) rank() uint64 {
+	return e.base + e.Allowed + e.Denied
+}
+
+// NewOriginStats 创建统计，maxOrigins <= 0 时取 100
+func NewOriginStats(maxOrigins int) *OriginStats {
+	return &OriginStats{MaxOrigins: maxOrigins}
+}
+
+func (s *OriginStats) record(origin string, allowed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = map[string]*originEntry{}
+	}
+	e, ok := s.counts[origin]
+	if !ok {
+		e = &originEntry{}
+		if len(s.counts) >= s.limit() {
+			e.base = s.evict()
+		}
+		s.counts[origin] = e
+	}
+	if allowed {
+		e.Allowed++
+	} else {
+		e.Denied++
+	}
+}
+
+func (s *OriginStats) limit() int {
+	if s.MaxOrigins <= 0 {
+		return 100
+	}
+	return s.MaxOrigins
+}
+
+// evict 淘汰排序最小的来源，计数并入 OverflowOrigin，返回其排序值
+func (s *OriginStats) evict() uint64 {
+	var min string
+	var minEntry *originEntry
+	for origin, e := range s.counts {
+		if minEntry == nil || e.rank() < minEntry.rank() || (e.rank() == minEntry.rank() && origin < min) {
+			min, minEntry = origin, e
+		}
+	}
+	delete(s.counts, min)
+	s.other.Allowed += minEntry.Allowed
+	s.other.Denied += minEntry.Denied
+	return minEntry.rank()
+}
+
+// Snapshot 返回当前各来源的请求数
+func (s *OriginStats) Snapshot() map[string]OriginCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := make(map[string]OriginCount, len(s.counts)+1)
+	for origin, e := range s.counts {
+		snapshot[origin] = e.OriginCount
+	}
+	if s.other != (OriginCount{}) {
+		snapshot[OverflowOrigin] = s.other
+	}
+	return snapshot
+}
+
+// Metrics 返回 Snapshot，实现 logger.MetricsSource，
+// 可以通过 logger.RegisterMetrics("cors", stats) 输出到 logger.MetricsHandler
+func (s *OriginStats) Metrics() interface{} {
+	return s.Snapshot()
+}
+
+// ServeHTTP 以 JSON 输出 Snapshot，可以直接挂到监控路由上
+func (s *OriginStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.Snapshot())
+}
@@ -0,0 +1,84 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AIntelligenceGame/bus/logger"
+)
+
+func TestOriginStatsZeroValue(t *testing.T) {
+	stats := &OriginStats{MaxOrigins: 50}
+	stats.record("https://a.example.com", true)
+	stats.record("https://a.example.com", false)
+	var zero OriginStats
+	zero.record("https://b.example.com", true)
+
+	if got, want := stats.Snapshot(), map[string]OriginCount{"https://a.example.com": {Allowed: 1, Denied: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot = %v, want %v", got, want)
+	}
+	if got := zero.Snapshot(); got["https://b.example.com"].Allowed != 1 {
+		t.Errorf("zero value Snapshot = %v", got)
+	}
+}
+
+func TestOriginStatsTopN(t *testing.T) {
+	stats := NewOriginStats(3)
+	// 先出现的偶发来源占满之后，请求多的 b、c 仍然能留下，之后的偶发来源也挤不掉它们
+	record := func(origin string, n int, allowed bool) {
+		for i := 0; i < n; i++ {
+			stats.record(origin, allowed)
+		}
+	}
+	record("r0", 1, true)
+	record("r1", 1, true)
+	record("r2", 1, true)
+	for i := 0; i < 20; i++ {
+		record("b", 1, true)
+		record("c", 1, false)
+	}
+	record("r3", 1, true)
+	record("r4", 1, true)
+	record("b", 10, true)
+	record("c", 10, false)
+
+	want := map[string]OriginCount{
+		"b":            {Allowed: 30},
+		"c":            {Denied: 30},
+		"r4":           {Allowed: 1},
+		OverflowOrigin: {Allowed: 4},
+	}
+	if got := stats.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot = %v, want %v", got, want)
+	}
+}
+
+func TestOriginStatsMiddleware(t *testing.T) {
+	stats := NewOriginStats(0)
+	h := HTTP(CorsConfig{AllowOrigins: []string{"https://ok.example.com"}, Stats: stats})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, origin := range []string{"https://ok.example.com", "https://ok.example.com", "https://bad.example.com", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	want := map[string]OriginCount{
+		"https://ok.example.com":  {Allowed: 2},
+		"https://bad.example.com": {Denied: 1},
+	}
+	if got := stats.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot = %v, want %v", got, want)
+	}
+
+	logger.RegisterMetrics("cors", stats)
+	defer logger.RegisterMetrics("cors", nil)
+	w := httptest.NewRecorder()
+	logger.MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if body := w.Body.String(); !strings.Contains(body, `"cors":{`) || !strings.Contains(body, `"https://bad.example.com":{"allowed":0,"denied":1}`) {
+		t.Errorf("metrics = %s", body)
+	}
+}
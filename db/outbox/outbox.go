@@ -0,0 +1,285 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/xxl-job/xxl-job-executor-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 事务发件箱：业务写入和事件写入在同一个事务中提交，再由后台 Dispatcher 投递，
+// 进程在两步之间退出也不会丢失通知。投递语义为至少一次，消费方需要按事件 ID 去重。
+
+// 事件状态
+const (
+	StatusPending   = 0 // 待投递
+	StatusPublished = 1 // 已投递
+	StatusDead      = 2 // 超过最大重试次数，不再投递
+)
+
+// OutboxEvent 发件箱中的一条事件
+type OutboxEvent struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateKey  string     `gorm:"size:191;index:idx_outbox_aggregate,priority:2" json:"aggregate_key"` // 同一个 key 的事件按写入顺序投递
+	Topic         string     `gorm:"size:191" json:"topic"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	Status        int        `gorm:"index:idx_outbox_aggregate,priority:1;index:idx_outbox_status" json:"-"`
+	Attempts      int        `json:"-"`
+	NextAttemptAt time.Time  `gorm:"index" json:"-"`
+	LeaseOwner    string     `gorm:"size:64" json:"-"`
+	LeaseUntil    *time.Time `json:"-"`
+	LastError     string     `gorm:"type:text" json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PublishedAt   *time.Time `json:"-"`
+}
+
+// TableName 发件箱表名
+func (OutboxEvent) TableName() string {
+	return "bus_outbox"
+}
+
+// Migrate 创建或更新发件箱表
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&OutboxEvent{})
+}
+
+// WithOutbox 在 tx 所在的事务中写入一条待投递事件，随业务数据一起提交或回滚
+func WithOutbox(tx *gorm.DB, event OutboxEvent) error {
+	if event.Topic == "" {
+		return errors.New("*** 事件 Topic 不能为空")
+	}
+	event.ID = 0
+	event.Status = StatusPending
+	event.Attempts = 0
+	event.NextAttemptAt = time.Now()
+	return tx.Create(&event).Error
+}
+
+// Publisher 事件投递接口
+type Publisher interface {
+	Publish(ctx context.Context, event OutboxEvent) error
+}
+
+// WebhookPublisher 把事件以 JSON POST 到 URL，返回非 2xx 视为失败
+type WebhookPublisher struct {
+	URL    string
+	Header http.Header
+	Client *http.Client
+}
+
+// Publish 投递一条事件
+func (p *WebhookPublisher) Publish(ctx context.Context, event OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range p.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %v 返回 %v", p.URL, resp.Status)
+	}
+	return nil
+}
+
+// Dispatcher 轮询发件箱并投递事件。
+// 多实例同时运行时通过租约列（lease_owner/lease_until）认领事件，租约过期的事件会被其他实例重新认领，
+// 因此投递成功但未来得及标记的事件会在租约过期后重复投递。
+type Dispatcher struct {
+	DB          *gorm.DB
+	Publisher   Publisher
+	Owner       string                           // 实例标识，默认主机名+进程号
+	Interval    time.Duration                    // 轮询间隔，默认 1s
+	BatchSize   int                              // 每轮最多认领的事件数，默认 100
+	Lease       time.Duration                    // 租约时长，默认 30s
+	MaxAttempts int                              // 最大投递次数，超过后进入 dead 状态，默认 10
+	Backoff     func(attempts int) time.Duration // 第 attempts 次失败后的等待时间，默认指数退避，最长 10 分钟
+}
+
+func (d *Dispatcher) defaults() {
+	if d.Owner == "" {
+		host, _ := os.Hostname()
+		d.Owner = fmt.Sprintf("%v-%d", host, os.Getpid())
+	}
+	if d.Interval <= 0 {
+		d.Interval = time.Second
+	}
+	if d.BatchSize <= 0 {
+		d.BatchSize = 100
+	}
+	if d.Lease <= 0 {
+		d.Lease = 30 * time.Second
+	}
+	if d.MaxAttempts <= 0 {
+		d.MaxAttempts = 10
+	}
+	if d.Backoff == nil {
+		d.Backoff = func(attempts int) time.Duration {
+			wait := time.Second << uint(attempts)
+			if wait <= 0 || wait > 10*time.Minute {
+				wait = 10 * time.Minute
+			}
+			return wait
+		}
+	}
+}
+
+// Run 持续轮询直到 ctx 结束
+func (d *Dispatcher) Run(ctx context.Context) error {
+	d.defaults()
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.Poll(ctx); err != nil && ctx.Err() == nil {
+			zap.L().Error("outbox poll", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 认领并投递一轮事件，返回投递成功的数量。
+// 同一个 AggregateKey 的事件按写入顺序逐条投递，一轮中可以投递多条，
+// 前面的事件处于退避中、被其他实例认领或本轮投递失败时，其后的事件留到之后的轮次；
+// AggregateKey 为空的事件互不约束顺序。
+func (d *Dispatcher) Poll(ctx context.Context) (int, error) {
+	d.defaults()
+	now := time.Now()
+	db := d.DB.WithContext(ctx)
+
+	// 同一个 key 中更早的事件还不能投递时，后面的事件不能越过它
+	blocking := db.Table("bus_outbox AS b").Select("1").
+		Where("b.aggregate_key = bus_outbox.aggregate_key AND b.status = ? AND b.id < bus_outbox.id", StatusPending).
+		Where("b.next_attempt_at > ? OR b.lease_until >= ?", now, now)
+	var candidates []OutboxEvent
+	err := db.Where("status = ?", StatusPending).
+		Where("next_attempt_at <= ?", now).
+		Where("lease_until IS NULL OR lease_until < ?", now).
+		Where("aggregate_key = '' OR NOT EXISTS (?)", blocking).
+		Order("id").Limit(d.BatchSize).
+		Find(&candidates).Error
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := map[string]bool{} // 本轮不再投递的 key
+	for _, event := range candidates {
+		if blocked[event.AggregateKey] {
+			continue
+		}
+		ok, err := d.deliver(ctx, event, now)
+		if err != nil {
+			return published, err
+		}
+		if !ok {
+			if event.AggregateKey != "" {
+				blocked[event.AggregateKey] = true
+			}
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// deliver 认领并投递一条事件，已被其他实例认领或投递失败时返回 false
+func (d *Dispatcher) deliver(ctx context.Context, event OutboxEvent, now time.Time) (bool, error) {
+	db := d.DB.WithContext(ctx)
+	leaseUntil := time.Now().Add(d.Lease)
+	claim := db.Model(&OutboxEvent{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", event.ID, StatusPending, now).
+		Where("lease_until IS NULL OR lease_until < ?", now).
+		Updates(map[string]interface{}{"lease_owner": d.Owner, "lease_until": leaseUntil})
+	if claim.Error != nil {
+		return false, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		// 已被其他实例认领
+		return false, nil
+	}
+
+	if err := d.Publisher.Publish(ctx, event); err != nil {
+		return false, d.fail(ctx, event, err)
+	}
+	err := db.Model(&OutboxEvent{}).
+		Where("id = ? AND lease_owner = ?", event.ID, d.Owner).
+		Updates(map[string]interface{}{"status": StatusPublished, "published_at": time.Now(), "lease_until": nil, "last_error": ""}).Error
+	return err == nil, err
+}
+
+// Task 返回执行一轮 Poll 的 xxl-job 任务，由调度中心定时触发时用它代替 Run，
+// 如 jobs.RegTask("outbox.dispatch", d.Task())
+func (d *Dispatcher) Task() xxl.TaskFunc {
+	return func(ctx context.Context, param *xxl.RunReq) string {
+		published, err := d.Poll(ctx)
+		if err != nil {
+			return fmt.Sprintf("published %d, error: %v", published, err)
+		}
+		return fmt.Sprintf("published %d", published)
+	}
+}
+
+// fail 记录一次投递失败，超过最大次数后进入 dead 状态
+func (d *Dispatcher) fail(ctx context.Context, event OutboxEvent, cause error) error {
+	attempts := event.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      cause.Error(),
+		"lease_until":     nil,
+		"next_attempt_at": time.Now().Add(d.Backoff(attempts)),
+	}
+	if attempts >= d.MaxAttempts {
+		updates["status"] = StatusDead
+		zap.L().Error("outbox event dead", zap.Uint64("id", event.ID), zap.String("topic", event.Topic), zap.Error(cause))
+	} else {
+		zap.L().Warn("outbox publish failed", zap.Uint64("id", event.ID), zap.Int("attempts", attempts), zap.Error(cause))
+	}
+	return d.DB.WithContext(ctx).Model(&OutboxEvent{}).
+		Where("id = ? AND lease_owner = ?", event.ID, d.Owner).
+		Updates(updates).Error
+}
+
+// Lag 返回最早一条待投递事件已等待的时间，没有待投递事件时为 0
+func (d *Dispatcher) Lag(ctx context.Context) (time.Duration, error) {
+	var event OutboxEvent
+	err := d.DB.WithContext(ctx).Where("status = ?", StatusPending).Order("id").Limit(1).Find(&event).Error
+	if err != nil || event.ID == 0 {
+		return 0, err
+	}
+	return time.Since(event.CreatedAt), nil
+}
+
+// Pending 返回待投递和 dead 状态的事件数
+func (d *Dispatcher) Pending(ctx context.Context) (pending, dead int64, err error) {
+	if err = d.DB.WithContext(ctx).Model(&OutboxEvent{}).Where("status = ?", StatusPending).Count(&pending).Error; err != nil {
+		return
+	}
+	err = d.DB.WithContext(ctx).Model(&OutboxEvent{}).Where("status = ?", StatusDead).Count(&dead).Error
+	return
+}
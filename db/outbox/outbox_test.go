@@ -0,0 +1,210 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type order struct {
+	ID   uint64 `gorm:"primaryKey"`
+	Name string
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&order{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// fakePublisher 记录投递的事件 ID，fail 中的事件投递失败
+type fakePublisher struct {
+	published []uint64
+	fail      map[uint64]bool
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event OutboxEvent) error {
+	if p.fail[event.ID] {
+		return errors.New("publish failed")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+// addEvents 依次写入各个 key 的事件，返回事件 ID
+func addEvents(t *testing.T, db *gorm.DB, keys ...string) []uint64 {
+	t.Helper()
+	for _, key := range keys {
+		if err := WithOutbox(db, OutboxEvent{AggregateKey: key, Topic: "test", Payload: "{}"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var ids []uint64
+	if err := db.Model(&OutboxEvent{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func status(t *testing.T, db *gorm.DB, id uint64) OutboxEvent {
+	t.Helper()
+	var event OutboxEvent
+	if err := db.First(&event, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestWithOutboxAtomic(t *testing.T) {
+	db := openDB(t)
+	rollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&order{Name: "a"}).Error; err != nil {
+			return err
+		}
+		if err := WithOutbox(tx, OutboxEvent{AggregateKey: "order-a", Topic: "order.created"}); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if err != rollback {
+		t.Fatalf("Transaction = %v, want %v", err, rollback)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&order{Name: "b"}).Error; err != nil {
+			return err
+		}
+		return WithOutbox(tx, OutboxEvent{AggregateKey: "order-b", Topic: "order.created"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var orders []string
+	var keys []string
+	db.Model(&order{}).Pluck("name", &orders)
+	db.Model(&OutboxEvent{}).Pluck("aggregate_key", &keys)
+	if !reflect.DeepEqual(orders, []string{"b"}) || !reflect.DeepEqual(keys, []string{"order-b"}) {
+		t.Errorf("orders = %v, events = %v, want only the committed transaction", orders, keys)
+	}
+
+	if err := WithOutbox(db, OutboxEvent{}); err == nil {
+		t.Errorf("WithOutbox without Topic should fail")
+	}
+}
+
+func TestPollOrder(t *testing.T) {
+	db := openDB(t)
+	ids := addEvents(t, db, "k1", "k1", "k2", "k1", "", "", "k2")
+	pub := &fakePublisher{fail: map[uint64]bool{ids[1]: true, ids[4]: true}}
+	d := &Dispatcher{DB: db, Publisher: pub, Backoff: func(int) time.Duration { return time.Hour }}
+
+	// k1 的第二条失败后本轮不再投递 k1；key 为空的事件互不影响
+	n, err := d.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{ids[0], ids[2], ids[5], ids[6]}
+	if n != len(want) || !reflect.DeepEqual(pub.published, want) {
+		t.Fatalf("Poll = %d, published %v, want %v", n, pub.published, want)
+	}
+	if e := status(t, db, ids[1]); e.Status != StatusPending || e.Attempts != 1 || e.LastError == "" {
+		t.Errorf("failed event = %+v", e)
+	}
+
+	// 失败的事件在退避中，其后的 k1 事件不能越过它
+	pub.published = nil
+	if n, err := d.Poll(context.Background()); err != nil || n != 0 {
+		t.Fatalf("Poll during backoff = %d, %v, published %v", n, err, pub.published)
+	}
+
+	// 退避结束后按顺序投递
+	pub.fail = nil
+	db.Model(&OutboxEvent{}).Where("status = ?", StatusPending).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if _, err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want = []uint64{ids[1], ids[3], ids[4]}
+	if !reflect.DeepEqual(pub.published, want) {
+		t.Errorf("published %v, want %v", pub.published, want)
+	}
+}
+
+func TestPollBatchSize(t *testing.T) {
+	db := openDB(t)
+	ids := addEvents(t, db, "k", "k", "k", "")
+	pub := &fakePublisher{}
+	d := &Dispatcher{DB: db, Publisher: pub, BatchSize: 2}
+	for _, want := range [][]uint64{ids[:2], ids[:4]} {
+		if _, err := d.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pub.published, want) {
+			t.Errorf("published %v, want %v", pub.published, want)
+		}
+	}
+}
+
+func TestPollLease(t *testing.T) {
+	db := openDB(t)
+	ids := addEvents(t, db, "k1", "k1", "k2")
+	pub := &fakePublisher{}
+	d := &Dispatcher{DB: db, Publisher: pub, Owner: "b"}
+
+	// 另一个实例认领了 k1 的第一条后退出，租约未过期前 k1 不投递
+	lease := time.Now().Add(time.Minute)
+	db.Model(&OutboxEvent{}).Where("id = ?", ids[0]).Updates(map[string]interface{}{"lease_owner": "a", "lease_until": lease})
+	if _, err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{ids[2]}; !reflect.DeepEqual(pub.published, want) {
+		t.Fatalf("published %v, want %v", pub.published, want)
+	}
+
+	// 租约过期后重新投递
+	db.Model(&OutboxEvent{}).Where("id = ?", ids[0]).Update("lease_until", time.Now().Add(-time.Second))
+	if _, err := d.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{ids[2], ids[0], ids[1]}; !reflect.DeepEqual(pub.published, want) {
+		t.Fatalf("published %v, want %v", pub.published, want)
+	}
+	if e := status(t, db, ids[0]); e.Status != StatusPublished || e.LeaseOwner != "b" || e.LeaseUntil != nil || e.PublishedAt == nil {
+		t.Errorf("redelivered event = %+v", e)
+	}
+}
+
+func TestPollDead(t *testing.T) {
+	db := openDB(t)
+	ids := addEvents(t, db, "k")
+	pub := &fakePublisher{fail: map[uint64]bool{ids[0]: true}}
+	d := &Dispatcher{DB: db, Publisher: pub, MaxAttempts: 2, Backoff: func(int) time.Duration { return -time.Second }}
+	for i := 0; i < 3; i++ {
+		if _, err := d.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e := status(t, db, ids[0]); e.Status != StatusDead || e.Attempts != 2 {
+		t.Errorf("event = %+v, want dead after 2 attempts", e)
+	}
+	pending, dead, err := d.Pending(context.Background())
+	if err != nil || pending != 0 || dead != 1 {
+		t.Errorf("Pending = %d, %d, %v, want 0, 1", pending, dead, err)
+	}
+}
@@ -28,7 +28,7 @@ func main() {
 		MaxProces -= 1
 	}
 	runtime.GOMAXPROCS(MaxProces)
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 	// 设置gin启动模式为生产模式
 
 	gin.SetMode(gin.ReleaseMode)
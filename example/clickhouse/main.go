@@ -11,7 +11,7 @@ import (
 )
 
 func main() {
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 
 	//初始换数据库连接信息
 
@@ -71,7 +71,7 @@ func main1() {
 }
 func main2() {
 	//并发能力控制
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 
 	if MaxProces > 2 {
 		MaxProces -= 1
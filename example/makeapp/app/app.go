@@ -20,7 +20,7 @@ var (
 )
 
 func Bus() {
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 	e()
 	//并发能力控制
 
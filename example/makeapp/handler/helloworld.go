@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -109,15 +110,16 @@ func e() {
 }
 func doMsi(m msi.Msi) (error, string) {
 	var outStr string
-	shell, err := xshell.Powershell()
+	shell, err := xshell.NewSession()
 	if err != nil {
 		return err, ""
 	}
 	defer shell.Exit()
 
+	opts := xshell.Options{Env: m.Env, Dir: m.Dir}
 	// ... 交互 in
 	for i := 0; i < len(m.Commands); i++ {
-		stdout, stderr, err := shell.Execute(m.Commands[i])
+		stdout, stderr, err := shell.ExecuteChecked(context.Background(), m.Commands[i], opts)
 		//中文解码
 
 		stdout = enc.ConvertString(stdout)
@@ -125,7 +127,8 @@ func doMsi(m msi.Msi) (error, string) {
 
 		outStr = fmt.Sprintf("%v", stdout)
 		if err != nil {
-			zap.L().Error("Making MSI File Error", zap.String("MSI stderr", stderr))
+			//失败时 err 中带有真实的退出码
+			zap.L().Error("Making MSI File Error", zap.String("MSI stderr", stderr), zap.Error(err))
 			return err, ""
 		}
 		zap.L().Info("Making MSI File ", zap.String("MSI stdout", stdout))
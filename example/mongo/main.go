@@ -17,7 +17,7 @@ import (
 )
 
 func main() {
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 
 	//定义 cfg 对象
 	var cfg mongodb.Info
@@ -22,7 +22,7 @@ var (
 )
 
 func main() {
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 	//并发能力控制
 
 	if MaxProces > 2 {
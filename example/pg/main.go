@@ -18,7 +18,7 @@ import (
 // *************** 注意要 defer rows.Close()
 
 func main() {
-	_ = logger.InitLogger(logger.LoggerConfig{})
+	_, _ = logger.InitLogger(logger.LoggerConfig{})
 	//定义 cfg 对象
 	var cfg postgresql.Info
 
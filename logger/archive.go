@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// archiveFailures 归档上传失败（含重试）的累计次数
+var archiveFailures uint64
+
+// ArchiveFailures 返回归档上传失败的累计次数，可用于监控
+func ArchiveFailures() uint64 {
+	return atomic.LoadUint64(&archiveFailures)
+}
+
+// ArchiveUploader 归档存储，ali-oss 的实现见 OSSUploader
+type ArchiveUploader interface {
+	// Upload 上传本地文件 file 到 key，返回 nil 表示已校验上传结果
+	Upload(key, file string) error
+	// Uploaded 判断 key 是否已存在且大小为 size
+	Uploaded(key string, size int64) (bool, error)
+}
+
+// ArchiveConfig 轮转日志的归档配置。
+//
+// 开启后 lumberjack 不再按 MaxBackups/MaxAge 清理旧日志，改由归档钩子在确认上传成功后清理，
+// 未上传成功的文件会一直保留在本地，进程重启后会重新检查并上传。
+type ArchiveConfig struct {
+	Uploader ArchiveUploader
+	// PathTemplate 对象路径模板，可用 {service}、{instance}、{date}、{file}，
+	// 默认 {service}/{instance}/{date}/{file}，其中 date 取备份文件的修改日期
+	PathTemplate string
+	Service      string
+	Instance     string        // 默认主机名
+	MaxRetries   int           // 单次上传的最大重试次数，默认 3
+	Backoff      time.Duration // 首次重试等待时间，之后每次翻倍，默认 1s
+	Interval     time.Duration // 定期重新检查未上传文件的间隔，默认 1 分钟
+}
+
+func (c *ArchiveConfig) withDefaults() {
+	if c.PathTemplate == "" {
+		c.PathTemplate = "{service}/{instance}/{date}/{file}"
+	}
+	if c.Instance == "" {
+		c.Instance, _ = os.Hostname()
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = time.Second
+	}
+	if c.Interval <= 0 {
+		c.Interval = time.Minute
+	}
+}
+
+// objectKey 按模板生成 file 的对象路径
+func (c *ArchiveConfig) objectKey(file string, modTime time.Time) string {
+	key := strings.NewReplacer(
+		"{service}", c.Service,
+		"{instance}", c.Instance,
+		"{date}", modTime.Format("2006-01-02"),
+		"{file}", filepath.Base(file),
+	).Replace(c.PathTemplate)
+	return strings.TrimPrefix(path.Clean(key), "/")
+}
+
+// archiveHook 上传已完成压缩的备份文件，并在上传成功后按 maxBackups/maxAge 清理本地文件。
+// suffix 为可上传文件的后缀，压缩尚未完成的文件等下一次触发时再处理。
+func archiveHook(c *ArchiveConfig, suffix string, maxBackups, maxAge int) rotateHook {
+	var mu sync.Mutex
+	uploaded := map[string]bool{}
+	return func(l *lumberjack.Logger) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		files, err := backupFiles(l)
+		if err != nil {
+			zap.L().Error("list rotated logs", zap.Error(err))
+			return
+		}
+		kept := 0
+		for _, f := range files {
+			if !strings.HasSuffix(f, suffix) {
+				continue
+			}
+			info, err := os.Stat(f)
+			if err != nil {
+				continue
+			}
+			if !uploaded[f] {
+				if err := c.upload(f, info); err != nil {
+					atomic.AddUint64(&archiveFailures, 1)
+					zap.L().Error("archive rotated log", zap.String("file", f), zap.Error(err))
+					continue
+				}
+				uploaded[f] = true
+			}
+
+			kept++
+			expired := maxAge > 0 && time.Since(info.ModTime()) > time.Duration(maxAge)*24*time.Hour
+			if (maxBackups > 0 && kept > maxBackups) || expired {
+				if err := os.Remove(f); err == nil {
+					delete(uploaded, f)
+				}
+			}
+		}
+	}
+}
+
+// upload 上传单个文件，已存在同样大小的对象时跳过（进程重启后的重复检查），失败按指数退避重试
+func (c *ArchiveConfig) upload(file string, info os.FileInfo) error {
+	key := c.objectKey(file, info.ModTime())
+	if ok, err := c.Uploader.Uploaded(key, info.Size()); err == nil && ok {
+		return nil
+	}
+	wait := c.Backoff
+	var err error
+	for i := 0; i <= c.MaxRetries; i++ {
+		if i > 0 {
+			atomic.AddUint64(&archiveFailures, 1)
+			zap.L().Warn("archive rotated log, retrying", zap.String("file", file), zap.Int("retry", i), zap.Error(err))
+			time.Sleep(wait)
+			wait *= 2
+		}
+		if err = c.Uploader.Upload(key, file); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// OSSUploader 上传到阿里云 OSS，Bucket 可由 ali-oss 包的 NewSession 获取的 Client 创建
+type OSSUploader struct {
+	Bucket *oss.Bucket
+}
+
+// Upload 带 Content-MD5 上传，上传后比对对象的大小和 MD5
+func (u *OSSUploader) Upload(key, file string) error {
+	sum, size, err := fileMD5(file)
+	if err != nil {
+		return err
+	}
+	if err := u.Bucket.PutObjectFromFile(key, file, oss.ContentMD5(sum)); err != nil {
+		return err
+	}
+	header, err := u.Bucket.GetObjectDetailedMeta(key)
+	if err != nil {
+		return err
+	}
+	if length := header.Get("Content-Length"); length != strconv.FormatInt(size, 10) {
+		return fmt.Errorf("uploaded size of %v is %v, want %v", key, length, size)
+	}
+	if remote := header.Get("Content-Md5"); remote != "" && remote != sum {
+		return fmt.Errorf("uploaded md5 of %v is %v, want %v", key, remote, sum)
+	}
+	return nil
+}
+
+// Uploaded 判断对象是否已存在且大小一致
+func (u *OSSUploader) Uploaded(key string, size int64) (bool, error) {
+	header, err := u.Bucket.GetObjectDetailedMeta(key)
+	if err != nil {
+		if e, ok := err.(oss.ServiceError); ok && e.StatusCode == 404 {
+			return false, nil
+		}
+		return false, err
+	}
+	return header.Get("Content-Length") == strconv.FormatInt(size, 10), nil
+}
+
+// fileMD5 返回文件 base64 编码的 MD5 和大小
+func fileMD5(file string) (string, int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := md5.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), size, nil
+}
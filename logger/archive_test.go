@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// memUploader 记录上传过的对象，fail 中的文件上传失败
+type memUploader struct {
+	mu      sync.Mutex
+	objects map[string]int64
+	fail    map[string]bool
+	calls   int
+}
+
+func (u *memUploader) Upload(key, file string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.calls++
+	if u.fail[filepath.Base(file)] {
+		return errors.New("upload failed")
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	u.objects[key] = info.Size()
+	return nil
+}
+
+func (u *memUploader) Uploaded(key string, size int64) (bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.objects[key]
+	return ok && s == size, nil
+}
+
+func TestArchiveObjectKey(t *testing.T) {
+	mod := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{"", "svc/host1/2024-03-05/app-1.log.gz"},
+		{"/logs/{date}/{service}-{file}", "logs/2024-03-05/svc-app-1.log.gz"},
+		{"{instance}//{file}", "host1/app-1.log.gz"},
+	}
+	for _, c := range cases {
+		conf := &ArchiveConfig{PathTemplate: c.template, Service: "svc", Instance: "host1"}
+		conf.withDefaults()
+		if got := conf.objectKey("/var/log/app-1.log.gz", mod); got != c.want {
+			t.Errorf("objectKey(%q) = %q, want %q", c.template, got, c.want)
+		}
+	}
+}
+
+func TestArchiveHook(t *testing.T) {
+	cases := []struct {
+		name       string
+		maxBackups int
+		fail       []string
+		kept       []string
+		uploaded   int
+	}{
+		{
+			name:     "全部上传，不清理",
+			kept:     []string{"app-2024-01-01T00-00-00.000.log.gz", "app-2024-01-02T00-00-00.000.log.gz", "app-2024-01-03T00-00-00.000.log.gz", "app-2024-01-04T00-00-00.000.log"},
+			uploaded: 3,
+		},
+		{
+			name:       "上传后按 MaxBackups 清理",
+			maxBackups: 1,
+			kept:       []string{"app-2024-01-03T00-00-00.000.log.gz", "app-2024-01-04T00-00-00.000.log"},
+			uploaded:   3,
+		},
+		{
+			name:       "上传失败的文件保留",
+			maxBackups: 1,
+			fail:       []string{"app-2024-01-01T00-00-00.000.log.gz"},
+			kept:       []string{"app-2024-01-01T00-00-00.000.log.gz", "app-2024-01-03T00-00-00.000.log.gz", "app-2024-01-04T00-00-00.000.log"},
+			uploaded:   2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{
+				"app.log",
+				"app-2024-01-01T00-00-00.000.log.gz",
+				"app-2024-01-02T00-00-00.000.log.gz",
+				"app-2024-01-03T00-00-00.000.log.gz",
+				"app-2024-01-04T00-00-00.000.log", // 尚未压缩完成
+			} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			uploader := &memUploader{objects: map[string]int64{}, fail: map[string]bool{}}
+			for _, f := range c.fail {
+				uploader.fail[f] = true
+			}
+			conf := &ArchiveConfig{Uploader: uploader, Service: "svc", MaxRetries: 1, Backoff: time.Millisecond}
+			conf.withDefaults()
+
+			hook := archiveHook(conf, ".gz", c.maxBackups, 0)
+			hook(&lumberjack.Logger{Filename: filepath.Join(dir, "app.log")})
+
+			if len(uploader.objects) != c.uploaded {
+				t.Errorf("uploaded %d objects, want %d", len(uploader.objects), c.uploaded)
+			}
+			files, err := backupFiles(&lumberjack.Logger{Filename: filepath.Join(dir, "app.log")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kept []string
+			for _, f := range files {
+				kept = append(kept, filepath.Base(f))
+			}
+			sort.Strings(kept)
+			if len(kept) != len(c.kept) {
+				t.Fatalf("kept %v, want %v", kept, c.kept)
+			}
+			for i := range kept {
+				if kept[i] != c.kept[i] {
+					t.Fatalf("kept %v, want %v", kept, c.kept)
+				}
+			}
+		})
+	}
+}
+
+func TestArchiveUploadSkipsExisting(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app-1.log.gz")
+	if err := os.WriteFile(file, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(file)
+	uploader := &memUploader{objects: map[string]int64{}, fail: map[string]bool{}}
+	conf := &ArchiveConfig{Uploader: uploader, Service: "svc", Instance: "h"}
+	conf.withDefaults()
+	uploader.objects[conf.objectKey(file, info.ModTime())] = info.Size()
+
+	if err := conf.upload(file, info); err != nil {
+		t.Fatal(err)
+	}
+	if uploader.calls != 0 {
+		t.Errorf("Upload called %d times for an existing object", uploader.calls)
+	}
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AuditConfig 审计日志配置。
+//
+// 审计日志是单独的只追加文件，不轮转。每条记录是一行 JSON，包含 time、event、调用方的字段、
+// prev_hash（上一条记录的 hash，第一条为空）和 hash（本行去掉 hash 字段后内容的 SHA-256），
+// 删除、修改或插入记录都会使链条断开，可以用 VerifyAudit 检查；
+// 只删掉末尾的记录不会断开链条，需要时由调用方另外保存最新的 hash
+type AuditConfig struct {
+	// Filename 审计日志文件名，与日志文件位于同一目录，默认 audit.log
+	Filename string
+}
+
+// auditHashLen hash 字段的长度（SHA-256 的十六进制）
+const auditHashLen = sha256.Size * 2
+
+// auditor 由 InitLogger 创建，未配置审计日志时为 nil
+var (
+	auditMu sync.Mutex
+	auditor *auditLog
+)
+
+type auditLog struct {
+	file *os.File
+	enc  zapcore.Encoder
+	prev string // 上一条记录的 hash
+}
+
+// openAudit 打开审计日志文件，已有记录时从最后一条的 hash 继续
+func openAudit(c *AuditConfig, dir string) (*auditLog, error) {
+	name := c.Filename
+	if name == "" {
+		name = "audit.log"
+	}
+	path := filepath.Join(dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	prev := ""
+	if last := lastLine(data); len(last) > 0 {
+		if _, hash, ok := splitAuditLine(last); ok {
+			prev = hash
+		} else {
+			log.Printf("审计日志 %v 的最后一行格式不正确，新的记录无法与之连接", path)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	encoderConfig := newEncoderConfig()
+	encoderConfig.MessageKey = "event"
+	encoderConfig.LevelKey = zapcore.OmitKey
+	encoderConfig.CallerKey = zapcore.OmitKey
+	encoderConfig.StacktraceKey = zapcore.OmitKey
+	encoderConfig.NameKey = zapcore.OmitKey
+	return &auditLog{file: f, enc: zapcore.NewJSONEncoder(encoderConfig), prev: prev}, nil
+}
+
+// setAuditor 替换审计日志，关闭之前打开的文件
+func setAuditor(a *auditLog) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditor != nil {
+		auditor.file.Close()
+	}
+	auditor = a
+}
+
+// Audit 写入一条审计记录，未配置 LoggerConfig.Audit 时忽略。
+// 写入失败时通过全局日志记录器报告错误
+func Audit(event string, fields ...zap.Field) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditor == nil {
+		return
+	}
+	if err := auditor.write(event, fields); err != nil {
+		zap.L().Error("写入审计日志失败", zap.String("event", event), zap.Error(err))
+	}
+}
+
+func (a *auditLog) write(event string, fields []zap.Field) error {
+	fields = append(fields[:len(fields):len(fields)], zap.String("prev_hash", a.prev))
+	buf, err := a.enc.EncodeEntry(zapcore.Entry{Time: time.Now(), Message: event}, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	body := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	line := make([]byte, 0, len(body)+auditHashLen+12)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, `,"hash":"`...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+	if _, err := a.file.Write(line); err != nil {
+		return err
+	}
+	a.prev = hash
+	return nil
+}
+
+// splitAuditLine 拆出一行审计记录中参与计算 hash 的内容和 hash
+func splitAuditLine(line []byte) ([]byte, string, bool) {
+	suffix := len(`,"hash":"`) + auditHashLen + len(`"}`)
+	if len(line) < suffix+1 {
+		return nil, "", false
+	}
+	i := len(line) - suffix
+	tail := line[i:]
+	if !bytes.HasPrefix(tail, []byte(`,"hash":"`)) || !bytes.HasSuffix(tail, []byte(`"}`)) {
+		return nil, "", false
+	}
+	hash := string(tail[len(`,"hash":"`) : len(tail)-len(`"}`)])
+	body := append(line[:i:i], '}')
+	return body, hash, true
+}
+
+func lastLine(data []byte) []byte {
+	data = bytes.TrimRight(data, "\n")
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		return data[i+1:]
+	}
+	return data
+}
+
+// VerifyAudit 校验审计日志的完整性：每行的 hash 与内容一致，且 prev_hash 等于上一行的 hash，
+// 第一行的 prev_hash 为空。返回第一处不一致，日志完整时返回 nil
+func VerifyAudit(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	prev := ""
+	n := 0
+	for scanner.Scan() {
+		n++
+		body, hash, ok := splitAuditLine(scanner.Bytes())
+		if !ok {
+			return fmt.Errorf("audit line %d: missing hash", n)
+		}
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != hash {
+			return fmt.Errorf("audit line %d: hash mismatch, entry was modified", n)
+		}
+		var entry struct {
+			PrevHash *string `json:"prev_hash"`
+		}
+		if err := json.Unmarshal(body, &entry); err != nil {
+			return fmt.Errorf("audit line %d: %v", n, err)
+		}
+		if entry.PrevHash == nil || *entry.PrevHash != prev {
+			return fmt.Errorf("audit line %d: chain broken, an entry before it was removed or reordered", n)
+		}
+		prev = hash
+	}
+	return scanner.Err()
+}
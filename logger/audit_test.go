@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// writeAudit 打开 dir 下的审计日志，依次写入 events 后关闭
+func writeAudit(t *testing.T, dir string, events ...string) {
+	t.Helper()
+	a, err := openAudit(&AuditConfig{}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.file.Close()
+	for _, event := range events {
+		if err := a.write(event, []zap.Field{zap.String("user", "u-"+event)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// auditLines 读取 dir 下审计日志的各行
+func auditLines(t *testing.T, dir string) [][]byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+func verifyLines(lines [][]byte) error {
+	return VerifyAudit(bytes.NewReader(append(bytes.Join(lines, []byte("\n")), '\n')))
+}
+
+func TestVerifyAudit(t *testing.T) {
+	dir := t.TempDir()
+	writeAudit(t, dir, "login", "grant", "revoke", "logout")
+	lines := auditLines(t, dir)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	if err := verifyLines(lines); err != nil {
+		t.Fatalf("VerifyAudit on an intact log = %v", err)
+	}
+	if !bytes.Contains(lines[0], []byte(`"prev_hash":""`)) || !bytes.Contains(lines[1], []byte(`"event":"grant"`)) {
+		t.Errorf("unexpected lines:\n%s\n%s", lines[0], lines[1])
+	}
+
+	cases := []struct {
+		name  string
+		lines [][]byte
+		want  string
+	}{
+		{"modified", [][]byte{lines[0], bytes.Replace(lines[1], []byte("u-grant"), []byte("u-admin"), 1), lines[2], lines[3]}, "line 2: hash mismatch"},
+		{"deleted", [][]byte{lines[0], lines[2], lines[3]}, "line 2: chain broken"},
+		{"deleted first", [][]byte{lines[1], lines[2], lines[3]}, "line 1: chain broken"},
+		{"reordered", [][]byte{lines[0], lines[2], lines[1], lines[3]}, "line 2: chain broken"},
+		{"inserted", [][]byte{lines[0], lines[1], lines[1], lines[2], lines[3]}, "line 3: chain broken"},
+		{"missing hash", [][]byte{lines[0], []byte(`{"event":"x"}`)}, "line 2: missing hash"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := verifyLines(c.lines)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("VerifyAudit = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestAuditReopen(t *testing.T) {
+	dir := t.TempDir()
+	writeAudit(t, dir, "a", "b")
+	writeAudit(t, dir, "c")
+	lines := auditLines(t, dir)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	if err := verifyLines(lines); err != nil {
+		t.Errorf("VerifyAudit after reopening = %v", err)
+	}
+	_, hash, _ := splitAuditLine(lines[1])
+	if !bytes.Contains(lines[2], []byte(`"prev_hash":"`+hash+`"`)) {
+		t.Errorf("reopened entry does not continue the chain:\n%s", lines[2])
+	}
+}
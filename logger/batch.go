@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 缓冲区满时的处理策略
+const (
+	BatchDrop  = "drop"  // 丢弃新日志并计数（默认），不阻塞写入
+	BatchBlock = "block" // 阻塞写入直到缓冲区有空位
+)
+
+// BatchConfig 批量写入配置
+type BatchConfig struct {
+	MaxEntries int           // 每批最多条数，默认 100
+	MaxBytes   int           // 每批最多字节数，默认 1MB
+	Interval   time.Duration // 最长刷新间隔，默认 1s
+	BufferSize int           // 等待发送的最大条数，默认 1024
+	Policy     string        // 缓冲区满时的策略，见 Batch* 常量
+}
+
+// BatchWriter 把日志攒成批量后一次写入远端，可作为 zapcore.WriteSyncer 使用。
+// 每批调用一次 sink.Write，内容为各条日志按顺序拼接；sink 实现了 Sync 时每批写入后调用。
+type BatchWriter struct {
+	sink    io.Writer
+	config  BatchConfig
+	queue   chan batchItem
+	dropped uint64
+	once    sync.Once
+	closed  chan struct{}
+	done    chan struct{}
+	err     error // 后台退出时尚未返回的发送错误，done 关闭后可读
+}
+
+type batchItem struct {
+	data  []byte
+	flush chan error // 不为空时表示 Sync 请求
+}
+
+// NewBatchWriter 创建批量写入器并启动后台刷新
+func NewBatchWriter(sink io.Writer, config BatchConfig) *BatchWriter {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 100
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 1024 * 1024
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1024
+	}
+	if config.Policy == "" {
+		config.Policy = BatchDrop
+	}
+	w := &BatchWriter{
+		sink:   sink,
+		config: config,
+		queue:  make(chan batchItem, config.BufferSize),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 写入一条日志，p 会被复制
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	item := batchItem{data: append([]byte(nil), p...)}
+	select {
+	case <-w.closed:
+		return 0, errors.New("batch writer closed")
+	default:
+	}
+	if w.config.Policy == BatchBlock {
+		select {
+		case w.queue <- item:
+		case <-w.closed:
+			return 0, errors.New("batch writer closed")
+		}
+		return len(p), nil
+	}
+	select {
+	case w.queue <- item:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Sync 把 Sync 之前写入的日志全部发送出去，返回上次 Sync 之后第一次发送失败的错误
+// （包括后台按条数、大小、时间间隔触发的发送）。Close 之后调用返回 nil
+func (w *BatchWriter) Sync() error {
+	flush := make(chan error, 1)
+	select {
+	case w.queue <- batchItem{flush: flush}:
+	case <-w.closed:
+		return nil
+	}
+	select {
+	case err := <-flush:
+		return err
+	case <-w.done:
+		// 请求在后台退出后才进入队列
+		select {
+		case err := <-flush:
+			return err
+		default:
+			return nil
+		}
+	}
+}
+
+// Dropped 返回因缓冲区满被丢弃的日志条数
+func (w *BatchWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close 发送队列中剩余的日志并停止后台刷新，返回上次 Sync 之后第一次发送失败的错误
+func (w *BatchWriter) Close() error {
+	w.once.Do(func() { close(w.closed) })
+	<-w.done
+	return w.err
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	var buf bytes.Buffer
+	entries := 0
+	var failed error // 上次 Sync 之后第一次发送失败的错误
+	flush := func() {
+		if entries == 0 {
+			return
+		}
+		_, err := w.sink.Write(buf.Bytes())
+		if s, ok := w.sink.(interface{ Sync() error }); ok && err == nil {
+			err = s.Sync()
+		}
+		buf.Reset()
+		entries = 0
+		if err != nil && failed == nil {
+			failed = err
+		}
+	}
+	handle := func(item batchItem) {
+		if item.flush != nil {
+			flush()
+			item.flush <- failed
+			failed = nil
+			return
+		}
+		if entries > 0 && buf.Len()+len(item.data) > w.config.MaxBytes {
+			flush()
+		}
+		buf.Write(item.data)
+		entries++
+		if entries >= w.config.MaxEntries || buf.Len() >= w.config.MaxBytes {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case item := <-w.queue:
+			handle(item)
+		case <-ticker.C:
+			flush()
+		case <-w.closed:
+			// 只有这里从队列读取，队列不为空时读取不会阻塞
+			for len(w.queue) > 0 {
+				handle(<-w.queue)
+			}
+			flush()
+			w.err = failed
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchSink 记录每批写入的内容，fail 不为空时每次写入都返回该错误
+type batchSink struct {
+	mu      sync.Mutex
+	batches []string
+	fail    error
+}
+
+func (s *batchSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return 0, s.fail
+	}
+	s.batches = append(s.batches, string(p))
+	return len(p), nil
+}
+
+func (s *batchSink) all() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.batches, "")
+}
+
+func TestBatchWriter(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  BatchConfig
+		lines   int
+		batches int
+	}{
+		{"按条数分批", BatchConfig{MaxEntries: 3, Interval: time.Hour}, 7, 3},
+		{"按字节数分批", BatchConfig{MaxBytes: 8, Interval: time.Hour}, 4, 4},
+		{"Close 发送队列中剩余的日志", BatchConfig{MaxEntries: 1000, Interval: time.Hour}, 500, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sink := &batchSink{}
+			w := NewBatchWriter(sink, c.config)
+			var want bytes.Buffer
+			for i := 0; i < c.lines; i++ {
+				line := []byte("line" + string(rune('a'+i%26)) + "\n")
+				want.Write(line)
+				if _, err := w.Write(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := sink.all(); got != want.String() {
+				t.Errorf("sink got %d bytes, want %d", len(got), want.Len())
+			}
+			if len(sink.batches) != c.batches {
+				t.Errorf("got %d batches, want %d", len(sink.batches), c.batches)
+			}
+			if _, err := w.Write([]byte("x")); err == nil {
+				t.Errorf("Write after Close should fail")
+			}
+		})
+	}
+}
+
+func TestBatchWriterErrors(t *testing.T) {
+	failure := errors.New("sink down")
+
+	// 后台按条数发送失败，由下一次 Sync 返回
+	sink := &batchSink{fail: failure}
+	w := NewBatchWriter(sink, BatchConfig{MaxEntries: 1, Interval: time.Hour})
+	_, _ = w.Write([]byte("a\n"))
+	if err := w.Sync(); !errors.Is(err, failure) {
+		t.Errorf("Sync = %v, want %v", err, failure)
+	}
+	if err := w.Sync(); err != nil {
+		t.Errorf("second Sync = %v, want nil", err)
+	}
+
+	// 关闭时最后一批发送失败，由 Close 返回
+	_, _ = w.Write([]byte("b\n"))
+	if err := w.Close(); !errors.Is(err, failure) {
+		t.Errorf("Close = %v, want %v", err, failure)
+	}
+	if err := w.Sync(); err != nil {
+		t.Errorf("Sync after Close = %v, want nil", err)
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaxBodySize GinLoggerConfig.MaxBodySize 的默认值
+const defaultMaxBodySize = 4096
+
+// readRequestBody 读取请求体的前 max+1 个字节用于记录，并把读出的部分接回请求体，后续处理函数看到的内容不变
+func readRequestBody(c *gin.Context, max int) []byte {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil
+	}
+	head, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(max)+1))
+	c.Request.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(head), c.Request.Body), Closer: c.Request.Body}
+	return head
+}
+
+// replayBody 先返回已读出的部分再读取原请求体，Close 关闭原请求体
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter 在写出响应的同时保留前 max+1 个字节
+type bodyWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+	max int
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	if n := w.max + 1 - w.buf.Len(); n > 0 {
+		if len(b) > n {
+			b = b[:n]
+		}
+		w.buf.Write(b)
+	}
+}
+
+// bodyText 返回用于记录的正文：超过 max 时截断，非文本类型只记录类型
+func bodyText(body []byte, contentType string, max int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !textContent(contentType) {
+		return "[binary " + contentType + "]"
+	}
+	if len(body) > max {
+		return string(body[:max]) + "...(truncated, over " + strconv.Itoa(max) + " bytes)"
+	}
+	return string(body)
+}
+
+// textContent 判断内容类型是否为文本，未指定类型时按文本处理
+func textContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-www-form-urlencoded", "application/graphql":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyText(t *testing.T) {
+	cases := []struct {
+		body        string
+		contentType string
+		want        string
+	}{
+		{"", "application/json", ""},
+		{`{"a":1}`, "application/json; charset=utf-8", `{"a":1}`},
+		{"a=1&b=2", "application/x-www-form-urlencoded", "a=1&b=2"},
+		{"<a/>", "application/soap+xml", "<a/>"},
+		{"plain", "", "plain"},
+		{"\x89PNG", "image/png", "[binary image/png]"},
+		{"x", "not a type", "[binary not a type]"},
+		{"0123456789", "text/plain", "01234567...(truncated, over 8 bytes)"},
+		{"01234567", "text/plain", "01234567"},
+	}
+	for _, c := range cases {
+		if got := bodyText([]byte(c.body), c.contentType, 8); got != c.want {
+			t.Errorf("bodyText(%q, %q) = %q, want %q", c.body, c.contentType, got, c.want)
+		}
+	}
+}
+
+func TestGinLoggerLogBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name     string
+		config   GinLoggerConfig
+		body     string
+		request  string // 日志中的 request_body
+		response string
+		logged   bool // 是否记录正文字段
+	}{
+		{"disabled", GinLoggerConfig{}, "hello", "", "", false},
+		{"enabled", GinLoggerConfig{LogBody: true}, "hello", "hello", "echo:hello", true},
+		{"truncated", GinLoggerConfig{LogBody: true, MaxBodySize: 3}, "hello", "hel...(truncated, over 3 bytes)", "ech...(truncated, over 3 bytes)", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			logs := observeGlobal(t)
+			var received string
+			r := gin.New()
+			r.Use(GinLoggerWithConfig(c.config))
+			r.POST("/echo", func(ctx *gin.Context) {
+				data, _ := io.ReadAll(ctx.Request.Body)
+				received = string(data)
+				ctx.String(http.StatusOK, "echo:%s", data)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(c.body))
+			req.Header.Set("Content-Type", "text/plain")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// 处理函数和客户端看到的内容不受记录影响
+			if received != c.body || w.Body.String() != "echo:"+c.body {
+				t.Errorf("handler got %q, client got %q", received, w.Body.String())
+			}
+			entries := logs.All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d log entries, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			_, hasRequest := fields["request_body"]
+			_, hasResponse := fields["response_body"]
+			if hasRequest != c.logged || hasResponse != c.logged {
+				t.Fatalf("request_body, response_body present = %v, %v, want %v", hasRequest, hasResponse, c.logged)
+			}
+			if c.logged && (fields["request_body"] != c.request || fields["response_body"] != c.response) {
+				t.Errorf("request_body, response_body = %q, %q, want %q, %q",
+					fields["request_body"], fields["response_body"], c.request, c.response)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureStdout 执行 fn 期间把标准输出重定向到管道，返回写入的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestConsole(t *testing.T) {
+	cases := []struct {
+		encoding string
+		json     bool
+	}{
+		{"", false},
+		{"console", false},
+		{"json", true},
+	}
+	for _, c := range cases {
+		t.Run(c.encoding, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("TEST_LOG_DIR", dir)
+			t.Setenv("TEST_LOG_LEVEL", "")
+			out := captureStdout(t, func() {
+				logger, _ := InitLogger(LoggerConfig{EnvVar: "TEST_LOG_DIR", Filename: "console.log", LevelEnvVar: "TEST_LOG_LEVEL",
+					Console: true, ConsoleEncoding: c.encoding})
+				logger.Info("hello console")
+				_ = logger.Sync()
+			})
+
+			line := strings.TrimSpace(out)
+			if !strings.Contains(line, "hello console") || !strings.Contains(line, "console_test.go:") {
+				t.Errorf("stdout = %q, want message and caller", out)
+			}
+			if isJSON := json.Valid([]byte(line)); isJSON != c.json {
+				t.Errorf("stdout is JSON = %v, want %v: %q", isJSON, c.json, out)
+			}
+			if !c.json && !strings.Contains(line, "\tINFO\t") {
+				t.Errorf("stdout = %q, want console encoding", out)
+			}
+
+			// 文件始终为 JSON
+			data, err := os.ReadFile(filepath.Join(dir, "console.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entry map[string]interface{}
+			if err := json.Unmarshal(data, &entry); err != nil {
+				t.Fatalf("file is not JSON: %v: %q", err, data)
+			}
+			if entry["msg"] != "hello console" || entry["time"] == nil || entry["caller"] == nil {
+				t.Errorf("file entry = %v", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ctxLoggerKey 请求级日志记录器在 gin.Context 和 request context 中的 key
+const ctxLoggerKey = "bus.logger"
+
+// debugLogger 由 InitLogger 创建的 debug 级别日志记录器
+var debugLogger *zap.Logger
+
+// DebugConfig 按请求提升日志级别的配置。
+// 请求头 Header 或查询参数 Query 的值非空且不为 "0" 时视为请求提升级别，
+// 但只有满足 Secret 或 AllowIPs 其中之一的请求才会生效，两者都为空时该功能关闭。
+type DebugConfig struct {
+	Header      string   // 默认 X-Debug
+	Query       string   // 默认 debug
+	TokenHeader string   // 携带密钥的请求头，默认 X-Debug-Token
+	Secret      string   // 密钥
+	AllowIPs    []string // 允许提升级别的客户端 IP
+}
+
+// GinDebug 对满足条件的请求使用 debug 级别的日志记录器，不影响全局日志级别。
+// 处理函数中通过 FromContext 获取当前请求的日志记录器。
+func GinDebug(cfg DebugConfig) gin.HandlerFunc {
+	if cfg.Header == "" {
+		cfg.Header = "X-Debug"
+	}
+	if cfg.Query == "" {
+		cfg.Query = "debug"
+	}
+	if cfg.TokenHeader == "" {
+		cfg.TokenHeader = "X-Debug-Token"
+	}
+	allow := make(map[string]struct{}, len(cfg.AllowIPs))
+	for _, ip := range cfg.AllowIPs {
+		allow[ip] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if debugLogger == nil || !debugRequested(c, cfg) {
+			c.Next()
+			return
+		}
+		_, allowed := allow[c.ClientIP()]
+		if !allowed && cfg.Secret != "" {
+			allowed = subtle.ConstantTimeCompare([]byte(c.GetHeader(cfg.TokenHeader)), []byte(cfg.Secret)) == 1
+		}
+		if !allowed {
+			zap.L().Warn("debug logging request denied", zap.String("ip", c.ClientIP()), zap.String("path", c.Request.URL.Path))
+			c.Next()
+			return
+		}
+
+		l := debugLogger.With(zap.Bool("debug_request", true))
+		c.Set(ctxLoggerKey, l)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxLoggerKey, l))
+		c.Next()
+	}
+}
+
+func debugRequested(c *gin.Context, cfg DebugConfig) bool {
+	v := c.GetHeader(cfg.Header)
+	if v == "" {
+		v = c.Query(cfg.Query)
+	}
+	return v != "" && v != "0"
+}
+
+// FromContext 返回当前请求的日志记录器，没有单独设置时返回全局日志记录器
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxLoggerKey).(*zap.Logger); ok {
+			return l
+		}
+	}
+	return zap.L()
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MustInit 初始化日志库，日志文件不可用时 panic，适合希望启动即失败的调用方
+func MustInit(config LoggerConfig) *zap.Logger {
+	if err := checkLogFile(config); err != nil {
+		panic(fmt.Sprintf("logger: 初始化日志失败: %v", err))
+	}
+	logger, _ := InitLogger(config)
+	return logger
+}
+
+// InitOrNop 初始化日志库，日志文件不可用时改为输出到 stderr 并返回 error，进程可以继续运行
+func InitOrNop(config LoggerConfig) (*zap.Logger, error) {
+	if err := checkLogFile(config); err != nil {
+		atomLevel.SetLevel(parseLevel(withDefaults(config)))
+		core := zapcore.NewCore(zapcore.NewJSONEncoder(newEncoderConfig()), zapcore.Lock(os.Stderr), atomLevel)
+		logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+		zap.ReplaceGlobals(logger)
+		logger.Error("初始化日志失败，日志改为输出到 stderr", zap.Error(err))
+		return logger, err
+	}
+	logger, _ := InitLogger(config)
+	return logger, nil
+}
+
+// checkLogFile 检查日志文件能否创建和写入
+func checkLogFile(config LoggerConfig) error {
+	path, err := logFilePath(withDefaults(config))
+	if err != nil {
+		return fmt.Errorf("获取当前工作目录失败: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// atomLevel InitLogger 创建的全局日志级别
+var atomLevel = zap.NewAtomicLevel()
+
+// AtomicLevel 返回全局日志级别，可以在运行时调用 SetLevel 修改，
+// 也可以作为 http.Handler 挂到管理接口上查看和修改级别
+func AtomicLevel() zap.AtomicLevel {
+	return atomLevel
+}
+
+var (
+	levelMu   sync.Mutex
+	baseLevel zapcore.Level   // 第一个 WithLevel 开始前的级别
+	overrides []zapcore.Level // 正在执行的 WithLevel 指定的级别
+)
+
+// WithLevel 在 fn 执行期间把全局日志级别降低到 level（已经更低时不变），fn 返回或 panic 后恢复。
+//
+// 日志级别是全局的，fn 执行期间其他 goroutine 的日志同样会按 level 输出。
+// 多个 WithLevel 嵌套或并发时取其中最低的级别，全部结束后才恢复为原级别；
+// 期间通过其他方式修改的级别会在最后一个 WithLevel 结束时被覆盖。
+// 只需要对单个请求输出 debug 日志时使用 GinDebug。
+func WithLevel(level zapcore.Level, fn func()) {
+	levelMu.Lock()
+	if len(overrides) == 0 {
+		baseLevel = atomLevel.Level()
+	}
+	overrides = append(overrides, level)
+	applyLevel()
+	levelMu.Unlock()
+
+	defer func() {
+		levelMu.Lock()
+		for i, l := range overrides {
+			if l == level {
+				overrides = append(overrides[:i], overrides[i+1:]...)
+				break
+			}
+		}
+		applyLevel()
+		levelMu.Unlock()
+	}()
+	fn()
+}
+
+// applyLevel 按 baseLevel 和 overrides 设置全局级别，调用方持有 levelMu
+func applyLevel() {
+	level := baseLevel
+	for _, l := range overrides {
+		if l < level {
+			level = l
+		}
+	}
+	atomLevel.SetLevel(level)
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := []struct {
+		level string
+		env   string
+		want  zapcore.Level
+	}{
+		{"", "", zapcore.InfoLevel},
+		{"debug", "", zapcore.DebugLevel},
+		{"WARN", "", zapcore.WarnLevel},
+		{"error", "", zapcore.ErrorLevel},
+		{"verbose", "", zapcore.InfoLevel},
+		{"error", "debug", zapcore.DebugLevel},
+		{"", "warn", zapcore.WarnLevel},
+	}
+	for _, c := range cases {
+		t.Setenv("TEST_LOG_LEVEL", c.env)
+		got := parseLevel(LoggerConfig{Level: c.level, LevelEnvVar: "TEST_LOG_LEVEL"})
+		if got != c.want {
+			t.Errorf("parseLevel(Level=%q, env=%q) = %v, want %v", c.level, c.env, got, c.want)
+		}
+	}
+}
+
+func TestInitLoggerLevel(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TEST_LOG_DIR", dir)
+	t.Setenv("TEST_LOG_LEVEL", "")
+	logger, level := InitLogger(LoggerConfig{EnvVar: "TEST_LOG_DIR", Filename: "level.log", Level: "warn", LevelEnvVar: "TEST_LOG_LEVEL"})
+	if level.Level() != zapcore.WarnLevel {
+		t.Fatalf("level = %v, want warn", level.Level())
+	}
+	if AtomicLevel().Level() != zapcore.WarnLevel {
+		t.Errorf("AtomicLevel() = %v, want warn", AtomicLevel().Level())
+	}
+
+	logger.Info("info-before")
+	logger.Warn("warn-before")
+	// 运行时修改返回的级别立即生效
+	level.SetLevel(zapcore.DebugLevel)
+	defer level.SetLevel(zapcore.InfoLevel)
+	logger.Debug("debug-after")
+	_ = logger.Sync()
+
+	data, err := os.ReadFile(filepath.Join(dir, "level.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for msg, want := range map[string]bool{"info-before": false, "warn-before": true, "debug-after": true} {
+		if strings.Contains(out, msg) != want {
+			t.Errorf("log contains %q = %v, want %v\n%s", msg, !want, want, out)
+		}
+	}
+}
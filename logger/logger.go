@@ -18,18 +18,174 @@ import (
 )
 
 type LoggerConfig struct {
-	EnvVar     string
+	EnvVar string
+	// Filename 日志文件名，位于 EnvVar 指定的目录下，默认 debug.log
+	Filename   string
 	MaxSize    int
 	MaxBackups int
 	MaxAge     int
+	// LocalTime 为 true 时轮转文件名中的时间使用本地时区，默认 UTC
+	LocalTime bool
+	// Level 初始日志级别：debug、info（默认）、warn、error，运行时可以通过 AtomicLevel 修改
+	Level string
+	// LevelEnvVar 覆盖 Level 的环境变量名，默认 LOG_LEVEL，环境变量为空时不覆盖
+	LevelEnvVar string
+	// Compression 轮转日志的压缩格式：gzip（默认）、zstd、none，不压缩时设置为 none
+	Compression string
+	// Archive 轮转日志上传归档，为 nil 时不归档
+	Archive *ArchiveConfig
+	// MaxTotalSize 当前日志与所有备份的总大小上限，单位MB，0 表示不限制；
+	// 超出时删除最旧的备份（开启压缩时只删除已压缩的），优先于 MaxBackups/MaxAge 和归档
+	MaxTotalSize int
+	// RuntimeStats 为 true 时 ERROR 及以上级别的日志附加 goroutines 和 heap_alloc 字段
+	RuntimeStats bool
+	// Sampling 自适应采样，吞吐量超过目标时按比例丢弃 ERROR 以下级别的日志，为 nil 时不采样
+	Sampling *SamplingConfig
+	// Syslog 除文件外同时输出到 syslog，为 nil 时不输出；连接失败时只输出到文件
+	Syslog *SyslogConfig
+	// Console 为 true 时除文件外同时输出到标准输出，便于容器环境采集
+	Console bool
+	// ConsoleEncoding 标准输出的格式：console（默认，便于阅读）、json；文件始终为 JSON
+	ConsoleEncoding string
+	// Audit 审计日志，为 nil 时 Audit 不写入，见 AuditConfig
+	Audit *AuditConfig
 }
 
-// InitLogger 初始化日志库，支持日志增强和日志轮转
-func InitLogger(config LoggerConfig) *zap.Logger {
+// InitLogger 初始化日志库，支持日志增强和日志轮转。
+// 返回的日志级别与 AtomicLevel 相同，可以在运行时调用 SetLevel 修改
+func InitLogger(config LoggerConfig) (*zap.Logger, zap.AtomicLevel) {
+	config = withDefaults(config)
+	path, err := logFilePath(config)
+	if err != nil {
+		log.Fatal("获取当前工作目录失败", err)
+	}
+
+	// 配置日志轮转
+	lumberjackLogger, hooks := newRotation(config, path)
+	var writer zapcore.WriteSyncer = zapcore.AddSync(lumberjackLogger)
+	var rotate *rotateWriter
+	if len(hooks) > 0 {
+		rotate = newRotateWriter(lumberjackLogger, hooks...)
+		if config.Archive != nil && config.Archive.Uploader != nil {
+			// gzip 压缩在轮转后异步完成，上传失败的文件也需要再次处理，定期重新触发
+			rotate.every(config.Archive.Interval)
+		}
+		writer = zapcore.AddSync(rotate)
+	}
+	// 重复调用 InitLogger 时停止上一次的定期触发
+	setRotation(rotate)
+
+	// 创建日志级别配置，默认为 Info
+	atomLevel.SetLevel(parseLevel(config))
+
+	// 设置日志输出配置
+	encoderConfig := newEncoderConfig()
+
+	var sampler *adaptiveSampler
+	if config.Sampling != nil && config.Sampling.Target > 0 {
+		sampler = newAdaptiveSampler(config.Sampling)
+	}
+
+	syslogWriter := dialSyslog(config.Syslog)
+
+	var audit *auditLog
+	if config.Audit != nil {
+		if audit, err = openAudit(config.Audit, filepath.Dir(path)); err != nil {
+			log.Printf("打开审计日志失败，审计记录不会写入：%v", err)
+		}
+	}
+	setAuditor(audit)
+
+	// 创建日志输出器
+	newCore := func(level zapcore.LevelEnabler) zapcore.Core {
+		core := zapcore.NewCore(
+			zapcore.NewJSONEncoder(encoderConfig), // 使用 JSON 格式输出
+			writer,                                // 设置日志输出到文件，支持日志轮转
+			level,                                 // 设置日志级别
+		)
+		if config.Console {
+			core = zapcore.NewTee(core, zapcore.NewCore(consoleEncoder(config, encoderConfig), zapcore.Lock(os.Stdout), level))
+		}
+		if syslogWriter != nil {
+			core = zapcore.NewTee(core, newSyslogCore(syslogWriter, zapcore.NewJSONEncoder(encoderConfig), level))
+		}
+		if config.RuntimeStats {
+			core = runtimeCore{core}
+		}
+		if sampler != nil {
+			core = samplingCore{core, sampler}
+		}
+		return core
+	}
+
+	// 创建生产环境的日志配置，并指定输出到文件
+	logger := zap.New(newCore(atomLevel), zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+
+	// 单个请求提升日志级别时使用的 debug 级别日志记录器，输出位置与全局一致
+	debugLogger = zap.New(newCore(zap.DebugLevel), zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+
+	// 替换全局日志记录器
+	zap.ReplaceGlobals(logger)
+
+	return logger, atomLevel
+}
+
+// Sync 在进程退出前调用：停止 InitLogger 启动的定期归档触发，并同步全局日志记录器的输出
+func Sync() error {
+	setRotation(nil)
+	return zap.L().Sync()
+}
+
+// newRotation 按配置创建轮转日志及轮转后执行的钩子（压缩、归档、总大小限制）
+func newRotation(config LoggerConfig, path string) (*lumberjack.Logger, []rotateHook) {
+	lumberjackLogger := &lumberjack.Logger{
+		Filename:   path,              // 日志文件路径
+		MaxSize:    config.MaxSize,    // 每个日志文件的最大尺寸，单位MB
+		MaxBackups: config.MaxBackups, // 保留的旧日志文件个数
+		MaxAge:     config.MaxAge,     // 保留旧日志文件的天数
+		LocalTime:  config.LocalTime,  // 轮转文件名使用本地时间
+	}
+
+	// 根据压缩格式选择轮转后的处理方式，gzip 直接使用 lumberjack 自带的压缩
+	var hooks []rotateHook
+	suffix := filepath.Ext(path)
+	switch strings.ToLower(config.Compression) {
+	case CompressZstd:
+		hooks = append(hooks, zstdHook)
+		suffix += ".zst"
+	case CompressNone:
+	case "", CompressGzip:
+		lumberjackLogger.Compress = true
+		suffix += ".gz"
+	default:
+		log.Printf("不支持的日志压缩格式 %v，使用 gzip", config.Compression)
+		lumberjackLogger.Compress = true
+		suffix += ".gz"
+	}
+
+	// 开启归档时由归档钩子在上传成功后清理旧日志
+	if config.Archive != nil && config.Archive.Uploader != nil {
+		config.Archive.withDefaults()
+		lumberjackLogger.MaxBackups, lumberjackLogger.MaxAge = 0, 0
+		hooks = append(hooks, archiveHook(config.Archive, suffix, config.MaxBackups, config.MaxAge))
+	}
+
+	if config.MaxTotalSize > 0 {
+		hooks = append(hooks, totalSizeHook(int64(config.MaxTotalSize)*1024*1024, suffix))
+	}
+
+	return lumberjackLogger, hooks
+}
+
+// withDefaults 填充配置的默认值
+func withDefaults(config LoggerConfig) LoggerConfig {
 	// 默认使用 LOG_DIR 环境变量，如果传递了自定义的环境变量名，则使用该名称
 	if config.EnvVar == "" {
 		config.EnvVar = "LOG_DIR"
 	}
+	if config.Filename == "" {
+		config.Filename = "debug.log"
+	}
 	if config.MaxSize == 0 {
 		config.MaxSize = 1
 	}
@@ -39,6 +195,31 @@ func InitLogger(config LoggerConfig) *zap.Logger {
 	if config.MaxAge == 0 {
 		config.MaxAge = 1
 	}
+	if config.LevelEnvVar == "" {
+		config.LevelEnvVar = "LOG_LEVEL"
+	}
+	return config
+}
+
+// parseLevel 返回初始日志级别，环境变量优先于 config.Level，无法识别时使用 Info
+func parseLevel(config LoggerConfig) zapcore.Level {
+	text := config.Level
+	if env := os.Getenv(config.LevelEnvVar); env != "" {
+		text = env
+	}
+	if text == "" {
+		return zap.InfoLevel
+	}
+	level, err := zapcore.ParseLevel(text)
+	if err != nil {
+		log.Printf("不支持的日志级别 %v，使用 info", text)
+		return zap.InfoLevel
+	}
+	return level
+}
+
+// logFilePath 返回日志文件路径，目录取自 config.EnvVar 指定的环境变量
+func logFilePath(config LoggerConfig) (string, error) {
 	// 获取环境变量 (例如: LOG_DIR 或 LOG_DIR222)
 	logDir := os.ExpandEnv("${" + config.EnvVar + "}")
 
@@ -47,76 +228,128 @@ func InitLogger(config LoggerConfig) *zap.Logger {
 		var err error
 		logDir, err = os.Getwd()
 		if err != nil {
-			log.Fatal("获取当前工作目录失败", err)
+			return "", err
 		}
 	}
 
-	// 检查目录是否存在，如果不存在则使用默认路径 'debug.log'
+	// 检查目录是否存在，如果不存在则使用当前目录
 	if _, err := os.Stat(logDir); os.IsNotExist(err) {
 		// 如果目录不存在，使用当前工作目录
 		logDir = "."
 	}
 
-	// 创建日志文件路径，使用 'debug.log' 作为默认日志文件名
-	logFilePath := filepath.Join(logDir, "debug.log")
-
-	// 配置日志轮转
-	lumberjackLogger := &lumberjack.Logger{
-		Filename:   logFilePath,       // 日志文件路径
-		MaxSize:    config.MaxSize,    // 每个日志文件的最大尺寸，单位MB
-		MaxBackups: config.MaxBackups, // 保留的旧日志文件个数
-		MaxAge:     config.MaxAge,     // 保留旧日志文件的天数
-		Compress:   true,              // 是否压缩旧日志
-	}
-
-	// 创建日志级别配置
-	atom := zap.NewAtomicLevel()
-	atom.SetLevel(zap.InfoLevel) // 设置默认日志级别为 Info
+	// 创建日志文件路径，文件名默认为 'debug.log'
+	return filepath.Join(logDir, config.Filename), nil
+}
 
-	// 设置日志输出配置
+// newEncoderConfig 日志输出配置
+func newEncoderConfig() zapcore.EncoderConfig {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "time"
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder // 设置时间戳格式
 	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 	// encoderConfig.EncodeCaller = zapcore.FullCallerEncoder  //显示完整路径
 	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder //仅显示文件名和行号
+	return encoderConfig
+}
 
-	// 创建日志输出器
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig), // 使用 JSON 格式输出
-		zapcore.AddSync(lumberjackLogger),     // 设置日志输出到文件，支持日志轮转
-		atom,                                  // 设置日志级别
-	)
-
-	// 创建生产环境的日志配置，并指定输出到文件
-	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
-
-	// 替换全局日志记录器
-	zap.ReplaceGlobals(logger)
+// consoleEncoder 返回标准输出使用的编码器，与文件使用相同的时间格式和调用者格式
+func consoleEncoder(config LoggerConfig, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	switch strings.ToLower(config.ConsoleEncoding) {
+	case "", "console":
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		log.Printf("不支持的标准输出格式 %v，使用 console", config.ConsoleEncoding)
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	}
+}
 
-	return logger
+// GinLoggerConfig GinLoggerWithConfig 的配置
+type GinLoggerConfig struct {
+	// SkipPaths 不记录日志的路径，如 /healthz；以 * 结尾时按前缀匹配，如 /debug/* 匹配 /debug/pprof
+	SkipPaths []string
+	// LogBody 为 true 时在日志中记录请求体和响应体（request_body、response_body），
+	// 非文本类型只记录类型，不影响后续处理函数读取请求体
+	LogBody bool
+	// MaxBodySize 记录的请求体、响应体的最大字节数，超出部分截断，默认 4096
+	MaxBodySize int
 }
 
-// GinLogger 接收gin框架默认的日志
+// GinLogger 接收gin框架默认的日志，记录所有请求，见 GinLoggerWithConfig
 func GinLogger() gin.HandlerFunc {
+	return GinLoggerWithConfig(GinLoggerConfig{})
+}
+
+// GinLoggerWithConfig 按配置记录 gin 的请求日志。
+// 请求 ID 取自请求头 RequestIDHeader，没有时生成 UUID，同时写入响应头，处理函数中通过 RequestID 获取；
+// 跳过的路径同样设置请求 ID，只是不记录日志
+func GinLoggerWithConfig(cfg GinLoggerConfig) gin.HandlerFunc {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxBodySize
+	}
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	var prefixes []string
+	for _, p := range cfg.SkipPaths {
+		if strings.HasSuffix(p, "*") {
+			prefixes = append(prefixes, strings.TrimSuffix(p, "*"))
+		} else {
+			skip[p] = struct{}{}
+		}
+	}
+	skipped := func(path string) bool {
+		if _, ok := skip[path]; ok {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
+		requestID := setRequestID(c)
+		if skipped(path) {
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		var writer *bodyWriter
+		if cfg.LogBody {
+			requestBody = readRequestBody(c, cfg.MaxBodySize)
+			writer = &bodyWriter{ResponseWriter: c.Writer, max: cfg.MaxBodySize}
+			c.Writer = writer
+		}
 		c.Next()
 
 		cost := time.Since(start)
-		zap.L().Info(
-			path,
+		fields := []zap.Field{
+			zap.String("request_id", requestID),
 			zap.Int("status", c.Writer.Status()),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
+			zap.String("route", c.FullPath()), // 匹配到的路由模板，如 /users/:id，未匹配时为空
+			zap.String("handler", c.HandlerName()),
 			zap.String("query", query),
 			zap.String("ip", c.ClientIP()),
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
 			zap.Duration("cost", cost),
-		)
+		}
+		if writer != nil {
+			fields = append(fields,
+				zap.String("request_body", bodyText(requestBody, c.ContentType(), cfg.MaxBodySize)),
+				zap.String("response_body", bodyText(writer.buf.Bytes(), writer.Header().Get("Content-Type"), cfg.MaxBodySize)),
+			)
+		}
+		FromContext(c).Info(path, fields...)
 	}
 }
 
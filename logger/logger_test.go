@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewRotation(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   LoggerConfig
+		compress bool
+		hooks    int
+	}{
+		{"defaults", LoggerConfig{}, true, 0},
+		{"custom", LoggerConfig{MaxSize: 10, MaxBackups: 3, MaxAge: 7, LocalTime: true, Compression: CompressGzip}, true, 0},
+		{"none", LoggerConfig{Compression: CompressNone}, false, 0},
+		{"zstd", LoggerConfig{Compression: CompressZstd}, false, 1},
+		{"unknown", LoggerConfig{Compression: "lz4"}, true, 0},
+		{"total size", LoggerConfig{Compression: "NONE", MaxTotalSize: 100}, false, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := withDefaults(c.config)
+			path := filepath.Join(t.TempDir(), config.Filename)
+			l, hooks := newRotation(config, path)
+			if l.Filename != path || l.MaxSize != config.MaxSize || l.MaxBackups != config.MaxBackups ||
+				l.MaxAge != config.MaxAge || l.LocalTime != config.LocalTime {
+				t.Errorf("lumberjack = %+v, config = %+v", l, config)
+			}
+			if l.Compress != c.compress {
+				t.Errorf("Compress = %v, want %v", l.Compress, c.compress)
+			}
+			if len(hooks) != c.hooks {
+				t.Errorf("len(hooks) = %d, want %d", len(hooks), c.hooks)
+			}
+		})
+	}
+}
+
+func TestLogFilePath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TEST_LOG_DIR", dir)
+	t.Setenv("TEST_LOG_MISSING", filepath.Join(dir, "missing"))
+	cases := []struct {
+		config LoggerConfig
+		want   string
+	}{
+		{LoggerConfig{EnvVar: "TEST_LOG_DIR"}, filepath.Join(dir, "debug.log")},
+		{LoggerConfig{EnvVar: "TEST_LOG_DIR", Filename: "app.log"}, filepath.Join(dir, "app.log")},
+		{LoggerConfig{EnvVar: "TEST_LOG_MISSING", Filename: "app.log"}, "app.log"},
+	}
+	for _, c := range cases {
+		got, err := logFilePath(withDefaults(c.config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("logFilePath(%+v) = %q, want %q", c.config, got, c.want)
+		}
+	}
+}
+
+func TestGinLoggerSkipPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		path   string
+		logged bool
+	}{
+		{"/healthz", false},
+		{"/healthz/live", true},
+		{"/debug", true},
+		{"/debug/", false},
+		{"/debug/pprof/heap", false},
+		{"/api/users", true},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			logs := observeGlobal(t)
+			r := gin.New()
+			r.Use(GinLoggerWithConfig(GinLoggerConfig{SkipPaths: []string{"/healthz", "/debug/*"}}))
+			r.NoRoute(func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+			if logged := logs.Len() > 0; logged != c.logged {
+				t.Errorf("logged = %v, want %v", logged, c.logged)
+			}
+			// 跳过的路径同样设置请求 ID
+			if w.Header().Get(RequestIDHeader) == "" {
+				t.Errorf("%s not set", RequestIDHeader)
+			}
+		})
+	}
+}
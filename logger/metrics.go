@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// MetricsSource 可以输出到 MetricsHandler 的指标来源，Metrics 的返回值按 JSON 序列化
+type MetricsSource interface {
+	Metrics() interface{}
+}
+
+var (
+	metricsMu      sync.Mutex
+	metricsSources = map[string]MetricsSource{}
+)
+
+// RegisterMetrics 以 name 注册指标来源，同名的来源会被替换，source 为 nil 时取消注册。
+// name 为 "logger" 时会被日志组件自身的指标覆盖
+func RegisterMetrics(name string, source MetricsSource) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if source == nil {
+		delete(metricsSources, name)
+		return
+	}
+	metricsSources[name] = source
+}
+
+// loggerMetrics 日志组件自身的指标
+type loggerMetrics struct {
+	Level           string  `json:"level"`
+	SamplingRate    float64 `json:"samplingRate"`
+	ArchiveFailures uint64  `json:"archiveFailures"`
+}
+
+// MetricsHandler 以 JSON 输出日志组件自身的指标（key 为 "logger"）和 RegisterMetrics 注册的各个来源，
+// 可以挂到管理接口上，如 mux.Handle("/metrics/logger", logger.MetricsHandler())
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metricsMu.Lock()
+		sources := make(map[string]MetricsSource, len(metricsSources))
+		for name, source := range metricsSources {
+			sources[name] = source
+		}
+		metricsMu.Unlock()
+
+		metrics := make(map[string]interface{}, len(sources)+1)
+		for name, source := range sources {
+			metrics[name] = source.Metrics()
+		}
+		metrics["logger"] = loggerMetrics{
+			Level:           atomLevel.Level().String(),
+			SamplingRate:    SamplingRate(),
+			ArchiveFailures: ArchiveFailures(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(metrics)
+	})
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type staticMetrics map[string]int
+
+func (m staticMetrics) Metrics() interface{} { return m }
+
+func TestMetricsHandler(t *testing.T) {
+	metrics := func() map[string]json.RawMessage {
+		w := httptest.NewRecorder()
+		MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		var got map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("%v: %s", err, w.Body.String())
+		}
+		return got
+	}
+
+	RegisterMetrics("test", staticMetrics{"requests": 3})
+	got := metrics()
+	if string(got["test"]) != `{"requests":3}` {
+		t.Errorf("test = %s", got["test"])
+	}
+	var l loggerMetrics
+	if err := json.Unmarshal(got["logger"], &l); err != nil || l.Level == "" || l.SamplingRate != SamplingRate() {
+		t.Errorf("logger = %s, %v", got["logger"], err)
+	}
+
+	RegisterMetrics("test", nil)
+	if _, ok := metrics()["test"]; ok {
+		t.Errorf("test metrics still present after unregistering")
+	}
+}
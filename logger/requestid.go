@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader GinLogger 读取和回写请求 ID 的请求头/响应头
+var RequestIDHeader = "X-Request-ID"
+
+// ctxRequestIDKey 请求 ID 在 gin.Context 和 request context 中的 key
+const ctxRequestIDKey = "bus.request_id"
+
+// maxRequestIDLen 请求头中的 ID 超过该长度时忽略并重新生成，避免日志被超长的值撑大
+const maxRequestIDLen = 128
+
+// setRequestID 取请求头中的请求 ID，没有时生成 UUID，写入 gin.Context、request context 和响应头
+func setRequestID(c *gin.Context) string {
+	id := c.GetHeader(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLen {
+		id = uuid.NewString()
+	}
+	c.Set(ctxRequestIDKey, id)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxRequestIDKey, id))
+	c.Header(RequestIDHeader, id)
+	return id
+}
+
+// RequestID 返回当前请求的 ID，ctx 可以是 *gin.Context 或 c.Request.Context()，未经过 GinLogger 时返回空
+func RequestID(ctx context.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Value(ctxRequestIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
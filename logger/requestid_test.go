@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeGlobal 把全局日志记录器替换为观察者，测试结束后恢复
+func observeGlobal(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zap.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+	return logs
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name     string
+		header   string
+		generate bool // 是否应重新生成
+	}{
+		{"from header", "req-123", false},
+		{"missing", "", true},
+		{"too long", strings.Repeat("x", maxRequestIDLen+1), true},
+		{"max length", strings.Repeat("x", maxRequestIDLen), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			logs := observeGlobal(t)
+			var fromGin, fromRequest string
+			r := gin.New()
+			r.Use(GinLogger())
+			r.GET("/ping", func(ctx *gin.Context) {
+				fromGin, fromRequest = RequestID(ctx), RequestID(ctx.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if c.header != "" {
+				req.Header.Set(RequestIDHeader, c.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if c.generate {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Errorf("%s = %q, want generated UUID", RequestIDHeader, id)
+				}
+			} else if id != c.header {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, id, c.header)
+			}
+			if fromGin != id || fromRequest != id {
+				t.Errorf("RequestID = %q, %q, want %q", fromGin, fromRequest, id)
+			}
+			entries := logs.All()
+			if len(entries) != 1 || entries[0].ContextMap()["request_id"] != id {
+				t.Errorf("log entries = %+v, want request_id %q", entries, id)
+			}
+		})
+	}
+
+	if id := RequestID(context.Background()); id != "" {
+		t.Errorf("RequestID(Background) = %q, want empty", id)
+	}
+}
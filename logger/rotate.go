@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 轮转日志的压缩格式
+const (
+	CompressGzip = "gzip" // lumberjack 自带的 gzip 压缩（默认）
+	CompressZstd = "zstd" // 轮转后由钩子重新压缩为 zstd
+	CompressNone = "none" // 不压缩
+)
+
+// rotateHook 轮转完成后执行的钩子，在后台 goroutine 中运行，不阻塞日志写入
+type rotateHook func(l *lumberjack.Logger)
+
+// rotateWriter 包装 lumberjack.Logger，按 lumberjack 相同的规则判断是否发生了轮转，
+// 轮转后异步触发钩子
+type rotateWriter struct {
+	*lumberjack.Logger
+	mu       sync.Mutex
+	size     int64
+	maxBytes int64
+	notify   chan struct{}
+	hooks    []rotateHook
+	done     chan struct{} // 关闭后停止 every 的定期触发
+	stopOnce sync.Once
+}
+
+// rotation InitLogger 创建的 rotateWriter，替换时停止之前的定期触发
+var (
+	rotationMu sync.Mutex
+	rotation   *rotateWriter
+)
+
+// setRotation 替换当前的 rotateWriter，停止之前的定期触发
+func setRotation(w *rotateWriter) {
+	rotationMu.Lock()
+	defer rotationMu.Unlock()
+	if rotation != nil && rotation != w {
+		rotation.stop()
+	}
+	rotation = w
+}
+
+func newRotateWriter(l *lumberjack.Logger, hooks ...rotateHook) *rotateWriter {
+	w := &rotateWriter{
+		Logger:   l,
+		maxBytes: int64(l.MaxSize) * 1024 * 1024,
+		notify:   make(chan struct{}, 1),
+		hooks:    hooks,
+		done:     make(chan struct{}),
+	}
+	if info, err := os.Stat(l.Filename); err == nil {
+		w.size = info.Size()
+	}
+	go w.run()
+	// 启动时处理上次进程遗留的轮转文件
+	w.trigger()
+	return w
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	rotated := w.size+int64(len(p)) > w.maxBytes
+	n, err := w.Logger.Write(p)
+	if rotated {
+		w.size = int64(n)
+	} else {
+		w.size += int64(n)
+	}
+	w.mu.Unlock()
+	if rotated {
+		w.trigger()
+	}
+	return n, err
+}
+
+// Rotate 手动轮转，同样会触发钩子
+func (w *rotateWriter) Rotate() error {
+	w.mu.Lock()
+	err := w.Logger.Rotate()
+	w.size = 0
+	w.mu.Unlock()
+	w.trigger()
+	return err
+}
+
+func (w *rotateWriter) trigger() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// every 每隔 d 触发一次钩子，直到调用 stop
+func (w *rotateWriter) every(d time.Duration) {
+	ticker := time.NewTicker(d)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.trigger()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop 停止 every 的定期触发，写入和轮转仍然可用
+func (w *rotateWriter) stop() {
+	w.stopOnce.Do(func() { close(w.done) })
+}
+
+func (w *rotateWriter) run() {
+	for range w.notify {
+		for _, hook := range w.hooks {
+			hook(w.Logger)
+		}
+	}
+}
+
+// backupFiles 返回 lumberjack 轮转出的备份文件（含已压缩的），按时间从新到旧排序
+func backupFiles(l *lumberjack.Logger) ([]string, error) {
+	dir := filepath.Dir(l.Filename)
+	base := filepath.Base(l.Filename)
+	ext := filepath.Ext(base)
+	prefix := base[:len(base)-len(ext)] + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == base || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if !strings.Contains(name[len(prefix):], ext) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	// 备份文件名中的时间格式可以直接按字典序比较
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	return files, nil
+}
+
+// zstdHook 把未压缩的备份文件压缩为 .zst，并按 MaxBackups/MaxAge 清理 .zst 文件
+// （lumberjack 只识别 .gz 后缀，不会清理 .zst）
+func zstdHook(l *lumberjack.Logger) {
+	files, err := backupFiles(l)
+	if err != nil {
+		zap.L().Error("list rotated logs", zap.Error(err))
+		return
+	}
+	ext := filepath.Ext(l.Filename)
+	kept := 0
+	for _, f := range files {
+		path := f
+		switch {
+		case strings.HasSuffix(f, ext):
+			path = f + ".zst"
+			if err := compressZstd(f, path); err != nil {
+				zap.L().Error("compress rotated log", zap.String("file", f), zap.Error(err))
+				continue
+			}
+		case !strings.HasSuffix(f, ext+".zst"):
+			continue
+		}
+		kept++
+		if l.MaxBackups > 0 && kept > l.MaxBackups {
+			_ = os.Remove(path)
+			continue
+		}
+		if l.MaxAge > 0 {
+			if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > time.Duration(l.MaxAge)*24*time.Hour {
+				_ = os.Remove(path)
+			}
+		}
+	}
+}
+
+// totalSizeHook 当前日志与所有备份的总大小超过 maxBytes 时，从最旧的备份开始删除后缀为 suffix 的文件，
+// 直到总大小不超过 maxBytes。开启压缩时 suffix 为压缩后的后缀，正在等待压缩的备份不会被删除
+func totalSizeHook(maxBytes int64, suffix string) rotateHook {
+	return func(l *lumberjack.Logger) {
+		files, err := backupFiles(l)
+		if err != nil {
+			zap.L().Error("list rotated logs", zap.Error(err))
+			return
+		}
+		var total int64
+		if info, err := os.Stat(l.Filename); err == nil {
+			total = info.Size()
+		}
+		sizes := make(map[string]int64, len(files))
+		for _, f := range files {
+			if info, err := os.Stat(f); err == nil {
+				sizes[f] = info.Size()
+				total += info.Size()
+			}
+		}
+		for i := len(files) - 1; i >= 0 && total > maxBytes; i-- {
+			f := files[i]
+			if !strings.HasSuffix(f, suffix) {
+				continue
+			}
+			if err := os.Remove(f); err != nil {
+				zap.L().Error("remove rotated log", zap.String("file", f), zap.Error(err))
+				continue
+			}
+			total -= sizes[f]
+		}
+	}
+}
+
+// compressZstd 压缩 src 到 dst，成功后删除 src
+func compressZstd(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	enc, err := zstd.NewWriter(out)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	if _, err = io.Copy(enc, in); err == nil {
+		err = enc.Close()
+	} else {
+		enc.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestRotateWriterEvery(t *testing.T) {
+	var calls int64
+	count := func(*lumberjack.Logger) { atomic.AddInt64(&calls, 1) }
+	newWriter := func() *rotateWriter {
+		w := newRotateWriter(&lumberjack.Logger{Filename: filepath.Join(t.TempDir(), "app.log"), MaxSize: 1}, count)
+		t.Cleanup(func() { w.Close() })
+		return w
+	}
+	// waitCalls 等待钩子至少执行 n 次
+	waitCalls := func(n int64) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt64(&calls) < n {
+			if time.Now().After(deadline) {
+				t.Fatalf("hook calls = %d, want at least %d", atomic.LoadInt64(&calls), n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	// stopped 确认定期触发已停止：等待已发出的触发处理完后，钩子不再执行
+	stopped := func() {
+		t.Helper()
+		time.Sleep(50 * time.Millisecond)
+		n := atomic.LoadInt64(&calls)
+		time.Sleep(50 * time.Millisecond)
+		if got := atomic.LoadInt64(&calls); got != n {
+			t.Errorf("hook calls = %d after stop, want %d", got, n)
+		}
+	}
+
+	// 替换为新的 rotateWriter 时停止旧的定期触发
+	w := newWriter()
+	w.every(5 * time.Millisecond)
+	setRotation(w)
+	waitCalls(3)
+	setRotation(newWriter())
+	stopped()
+
+	// Sync 停止当前的定期触发，重复停止不会 panic
+	atomic.StoreInt64(&calls, 0)
+	w = newWriter()
+	w.every(5 * time.Millisecond)
+	setRotation(w)
+	waitCalls(3)
+	if err := Sync(); err != nil {
+		t.Logf("Sync: %v", err)
+	}
+	stopped()
+	w.stop()
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// runtimeCore 在 ERROR 及以上级别的日志中附加 goroutines 和 heap_alloc 字段，
+// 只在写入这些级别时才读取运行时数据（runtime.ReadMemStats 会短暂暂停所有 goroutine）
+type runtimeCore struct {
+	zapcore.Core
+}
+
+func (c runtimeCore) With(fields []zapcore.Field) zapcore.Core {
+	return runtimeCore{c.Core.With(fields)}
+}
+
+func (c runtimeCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c runtimeCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level >= zapcore.ErrorLevel {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		fields = append(fields, zap.Int("goroutines", runtime.NumGoroutine()), zap.Uint64("heap_alloc", ms.HeapAlloc))
+	}
+	return c.Core.Write(entry, fields)
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig 自适应采样配置
+type SamplingConfig struct {
+	// Target 每秒最多输出的日志条数（ERROR 及以上级别不计入也不丢弃）
+	Target int
+	// Window 统计吞吐量的时间窗口，默认 1s；每个窗口结束时按该窗口的条数重新计算采样率
+	Window time.Duration
+}
+
+// samplingEvery 当前每多少条保留 1 条，未开启采样时为 1
+var samplingEvery uint64 = 1
+
+// SamplingRate 返回当前的采样率（保留的比例，0~1），未开启采样或吞吐量低于目标时为 1，可用于监控
+func SamplingRate() float64 {
+	return 1 / float64(atomic.LoadUint64(&samplingEvery))
+}
+
+// adaptiveSampler 按上一个窗口的吞吐量调整采样率：超过目标时每 N 条保留 1 条，
+// 流量回落后下一个窗口即恢复
+type adaptiveSampler struct {
+	limit  float64 // 每个窗口的目标条数
+	window time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	seen  uint64 // 当前窗口内的条数
+	seq   uint64
+}
+
+func newAdaptiveSampler(c *SamplingConfig) *adaptiveSampler {
+	window := c.Window
+	if window <= 0 {
+		window = time.Second
+	}
+	atomic.StoreUint64(&samplingEvery, 1)
+	return &adaptiveSampler{
+		limit:  float64(c.Target) * window.Seconds(),
+		window: window,
+	}
+}
+
+// keep 判断该条日志是否输出
+func (s *adaptiveSampler) keep(entry zapcore.Entry) bool {
+	if entry.Level >= zapcore.ErrorLevel {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry.Time.Sub(s.start) >= s.window {
+		every := uint64(1)
+		if s.limit > 0 && float64(s.seen) > s.limit {
+			every = uint64(math.Ceil(float64(s.seen) / s.limit))
+		}
+		atomic.StoreUint64(&samplingEvery, every)
+		s.start, s.seen = entry.Time, 0
+	}
+	s.seen++
+	s.seq++
+	return s.seq%atomic.LoadUint64(&samplingEvery) == 0
+}
+
+// samplingCore 按 adaptiveSampler 丢弃部分日志
+type samplingCore struct {
+	zapcore.Core
+	sampler *adaptiveSampler
+}
+
+func (c samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return samplingCore{c.Core.With(fields), c.sampler}
+}
+
+func (c samplingCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) || !c.sampler.keep(entry) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
@@ -0,0 +1,106 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log"
+	"log/syslog"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogConfig 同时输出到 syslog 的配置
+type SyslogConfig struct {
+	// Network 连接方式：udp、tcp、unix 等，为空时连接本机的 syslog 服务（Address 被忽略）
+	Network string
+	// Address syslog 服务地址，如 127.0.0.1:514
+	Address string
+	// Facility 设施名称：user（默认）、daemon、local0 ~ local7 等
+	Facility string
+	// Tag 日志标签，为空时使用进程名
+	Tag string
+}
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern": syslog.LOG_KERN, "user": syslog.LOG_USER, "mail": syslog.LOG_MAIL,
+	"daemon": syslog.LOG_DAEMON, "auth": syslog.LOG_AUTH, "syslog": syslog.LOG_SYSLOG,
+	"lpr": syslog.LOG_LPR, "news": syslog.LOG_NEWS, "uucp": syslog.LOG_UUCP,
+	"cron": syslog.LOG_CRON, "authpriv": syslog.LOG_AUTHPRIV, "ftp": syslog.LOG_FTP,
+	"local0": syslog.LOG_LOCAL0, "local1": syslog.LOG_LOCAL1, "local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3, "local4": syslog.LOG_LOCAL4, "local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6, "local7": syslog.LOG_LOCAL7,
+}
+
+// dialSyslog 连接 syslog，未配置时返回 nil；连接失败时打印警告并返回 nil，日志只输出到文件
+func dialSyslog(c *SyslogConfig) *syslog.Writer {
+	if c == nil {
+		return nil
+	}
+	facility := syslog.LOG_USER
+	if c.Facility != "" {
+		f, ok := syslogFacilities[strings.ToLower(c.Facility)]
+		if !ok {
+			log.Printf("不支持的 syslog 设施 %v，使用 user", c.Facility)
+		} else {
+			facility = f
+		}
+	}
+	w, err := syslog.Dial(c.Network, c.Address, facility|syslog.LOG_INFO, c.Tag)
+	if err != nil {
+		log.Printf("连接 syslog %v %v 失败，日志只输出到文件：%v", c.Network, c.Address, err)
+		return nil
+	}
+	return w
+}
+
+// syslogCore 将日志按级别写入 syslog 对应的严重程度
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslog.Writer
+}
+
+func newSyslogCore(w *syslog.Writer, enc zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+	return &syslogCore{LevelEnabler: level, enc: enc, w: w}
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, w: c.w}
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+	switch {
+	case entry.Level >= zapcore.DPanicLevel:
+		return c.w.Crit(msg)
+	case entry.Level >= zapcore.ErrorLevel:
+		return c.w.Err(msg)
+	case entry.Level == zapcore.WarnLevel:
+		return c.w.Warning(msg)
+	case entry.Level == zapcore.InfoLevel:
+		return c.w.Info(msg)
+	default:
+		return c.w.Debug(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
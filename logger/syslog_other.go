@@ -0,0 +1,31 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"log"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogConfig 同时输出到 syslog 的配置，当前平台不支持 syslog，配置后只输出到文件
+type SyslogConfig struct {
+	Network  string
+	Address  string
+	Facility string
+	Tag      string
+}
+
+type syslogWriter struct{}
+
+func dialSyslog(c *SyslogConfig) *syslogWriter {
+	if c == nil {
+		return nil
+	}
+	log.Printf("当前平台不支持 syslog，日志只输出到文件")
+	return nil
+}
+
+func newSyslogCore(w *syslogWriter, enc zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+	return zapcore.NewNopCore()
+}
@@ -116,10 +116,12 @@ var (
 //定义MSI信息
 
 type Msi struct {
-	Task     int64    `json:"task"`
-	Svc      string   `json:"svc"`
-	Display  string   `json:"display"`
-	Commands []string `json:"commands"`
+	Task     int64             `json:"task"`
+	Svc      string            `json:"svc"`
+	Display  string            `json:"display"`
+	Commands []string          `json:"commands"`
+	Env      map[string]string `json:"env"` // 执行命令时附加的环境变量，如 WIX、PATH
+	Dir      string            `json:"dir"` // 执行命令的工作目录
 }
 
 //获取json文件
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// 列所属的表不是物理表时 ColumnInfo、OrderByInfo、GroupByInfo 中 Table 的取值，别名见 TableAlias
+const (
+	TableDerived = "<derived>" // FROM 中的子查询
+	TableCte     = "<cte>"     // WITH 中定义的 CTE
+)
+
+// columnRef 表达式只是一个列名时返回限定符和列名，否则返回空
+func columnRef(node antlr.Tree) (string, string) {
+	for {
+		if c, ok := node.(*FullColumnNameContext); ok {
+			return splitColumnName(c.GetText())
+		}
+		if node == nil || node.GetChildCount() != 1 {
+			return "", ""
+		}
+		node = node.GetChild(0)
+	}
+}
+
+// resolveAliases 将最外层查询中列的表限定符解析为表名：别名解析为真实表名并保留在 TableAlias，
+// 派生表和 CTE 解析为 TableDerived、TableCte；没有限定符的列只在 FROM 中只有一个表时解析，
+// 无法解析的限定符（如外层查询的别名）保持原样
+func (l *sqlListener) resolveAliases() {
+	for i := range l.result.Columns {
+		c := &l.result.Columns[i]
+		if c.Name != "" {
+			c.Table, c.TableAlias = l.resolveQualifier(c.Table)
+		}
+	}
+	for i := range l.result.OrderBy {
+		o := &l.result.OrderBy[i]
+		if o.Column != "" && !l.selectAlias(o.Table, o.Column) {
+			o.Table, o.TableAlias = l.resolveQualifier(o.Table)
+		}
+	}
+	for i := range l.result.GroupByItems {
+		g := &l.result.GroupByItems[i]
+		if g.Column != "" && !l.selectAlias(g.Table, g.Column) {
+			g.Table, g.TableAlias = l.resolveQualifier(g.Table)
+		}
+	}
+}
+
+// selectAlias ORDER BY、GROUP BY 中没有限定符的名称是否为 SELECT 列表中的别名
+func (l *sqlListener) selectAlias(qualifier, name string) bool {
+	if qualifier != "" {
+		return false
+	}
+	for _, c := range l.result.Columns {
+		if c.Alias != "" && strings.EqualFold(c.Alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveQualifier 返回限定符对应的表名和别名，限定符就是表名时别名为空
+func (l *sqlListener) resolveQualifier(qualifier string) (string, string) {
+	if qualifier == "" {
+		if len(l.sources) != 1 {
+			return "", ""
+		}
+		table, _ := l.sourceTable(l.sources[0])
+		return table, ""
+	}
+	s, ok := resolveTable(qualifier, l.sources, l.fold)
+	if !ok {
+		return qualifier, ""
+	}
+	table, physical := l.sourceTable(s)
+	if physical && s.name == s.table {
+		return table, ""
+	}
+	return table, qualifier
+}
+
+// sourceTable 返回表来源的表名，派生表和 CTE 返回 TableDerived、TableCte 且 physical 为 false
+func (l *sqlListener) sourceTable(s tableSource) (table string, physical bool) {
+	switch {
+	case s.table == "":
+		return TableDerived, false
+	case l.ctes[s.table]:
+		return TableCte, false
+	}
+	return s.table, true
+}
@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// ALTER TABLE 操作类型
+const (
+	AlterAddColumn     = "ADD COLUMN"
+	AlterDropColumn    = "DROP COLUMN"
+	AlterModifyColumn  = "MODIFY COLUMN"
+	AlterChangeColumn  = "CHANGE COLUMN"
+	AlterRenameColumn  = "RENAME COLUMN"
+	AlterColumnDefault = "ALTER COLUMN DEFAULT" // ALTER COLUMN SET/DROP DEFAULT
+	AlterAddIndex      = "ADD INDEX"            // 含 UNIQUE、FULLTEXT、SPATIAL 和主键，类型见 IndexKind
+	AlterDropIndex     = "DROP INDEX"           // DROP PRIMARY KEY 时 Index 为 PRIMARY
+	AlterRenameIndex   = "RENAME INDEX"
+	AlterRenameTable   = "RENAME TABLE"
+	AlterOther         = "OTHER" // 分区、表选项等其他操作，原文见 Text
+)
+
+// IndexPrimary 主键，用于 AlterOperation.IndexKind，其他索引类型见 Index* 常量
+const IndexPrimary = "PRIMARY"
+
+// AlterOperation ALTER TABLE 中的一个操作
+type AlterOperation struct {
+	Kind       string   `json:"kind"`       // 见 Alter* 常量
+	Column     string   `json:"column"`     // 操作的列，CHANGE/RENAME 时为原列名
+	NewColumn  string   `json:"newColumn"`  // CHANGE/RENAME 后的列名
+	Type       string   `json:"type"`       // 列定义中的数据类型原文，如 varchar(64)
+	Definition string   `json:"definition"` // 完整的列定义原文，如 varchar(64) NOT NULL DEFAULT ''
+	After      string   `json:"after"`      // AFTER 指定的列
+	First      bool     `json:"first"`      // 是否指定了 FIRST
+	Index      string   `json:"index"`      // 操作的索引名，RENAME INDEX 时为原索引名
+	IndexKind  string   `json:"indexKind"`  // ADD INDEX 的索引类型，见 Index* 常量
+	Columns    []string `json:"columns"`    // ADD INDEX 的索引列
+	NewName    string   `json:"newName"`    // RENAME INDEX/RENAME TABLE 后的名称
+	Text       string   `json:"text"`       // 操作的 SQL 原文
+}
+
+// AlterTable 一条 ALTER TABLE 语句
+type AlterTable struct {
+	Table      string           `json:"table"`
+	Operations []AlterOperation `json:"operations"`
+}
+
+// IsOnlineSafe 判断语句中的操作是否都能在线执行（按 MySQL 8.0 的 Online DDL：
+// 只修改元数据，或以 INPLACE 方式执行且不重建表、不阻塞并发 DML）。
+// 包括改名、修改默认值、删除和新增普通/唯一索引、在末尾追加列；
+// 修改列类型、调整列顺序、主键和全文/空间索引等会重建表或锁表的操作不安全，未识别的操作同样视为不安全
+func (t *AlterTable) IsOnlineSafe() bool {
+	for _, op := range t.Operations {
+		switch op.Kind {
+		case AlterRenameColumn, AlterRenameIndex, AlterRenameTable, AlterColumnDefault:
+		case AlterAddColumn:
+			if op.First || op.After != "" {
+				return false
+			}
+		case AlterAddIndex:
+			if op.IndexKind != IndexNormal && op.IndexKind != IndexUnique {
+				return false
+			}
+		case AlterDropIndex:
+			if op.Index == IndexPrimary {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ParseAlter 解析 sql 中的 ALTER TABLE 语句，其他语句忽略，语法错误时返回 error
+func ParseAlter(sql string) ([]AlterTable, error) {
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := NewMySqlParser(stream)
+
+	errs := &syntaxErrorListener{}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errs)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errs)
+
+	alters := &alterListener{}
+	antlr.ParseTreeWalkerDefault.Walk(alters, parseRoot(p))
+	if len(errs.errors) > 0 {
+		return nil, errs.errors[0]
+	}
+	return alters.tables, nil
+}
+
+type alterListener struct {
+	*BaseMySqlParserListener
+	tables []AlterTable
+}
+
+func (l *alterListener) EnterAlterTable(ctx *AlterTableContext) {
+	l.tables = append(l.tables, alterTable(ctx))
+}
+
+func alterTable(ctx *AlterTableContext) AlterTable {
+	table := AlterTable{Operations: []AlterOperation{}}
+	if ctx.TableName() != nil {
+		table.Table = unquoteName(ctx.TableName().GetText())
+	}
+	for _, spec := range ctx.AllAlterSpecification() {
+		table.Operations = append(table.Operations, alterOperations(spec)...)
+	}
+	return table
+}
+
+func alterOperations(spec IAlterSpecificationContext) []AlterOperation {
+	op := AlterOperation{Kind: AlterOther, Columns: []string{}, Text: originalText(spec)}
+	switch s := spec.(type) {
+	case *AlterByAddColumnContext:
+		op.Kind = AlterAddColumn
+		op.Column = uidText(s.Uid(0))
+		op.Type = dataType(s.ColumnDefinition())
+		op.Definition = originalText(s.ColumnDefinition())
+		op.After = uidText(s.Uid(1))
+		op.First = s.FIRST() != nil
+	case *AlterByAddColumnsContext:
+		// ADD COLUMN (a int, b int) 拆成多个操作
+		ops := make([]AlterOperation, 0, len(s.AllUid()))
+		for i, uid := range s.AllUid() {
+			ops = append(ops, AlterOperation{
+				Kind:       AlterAddColumn,
+				Column:     uidText(uid),
+				Type:       dataType(s.ColumnDefinition(i)),
+				Definition: originalText(s.ColumnDefinition(i)),
+				Columns:    []string{},
+				Text:       op.Text,
+			})
+		}
+		return ops
+	case *AlterByDropColumnContext:
+		op.Kind = AlterDropColumn
+		op.Column = uidText(s.Uid())
+	case *AlterByModifyColumnContext:
+		op.Kind = AlterModifyColumn
+		op.Column = uidText(s.Uid(0))
+		op.Type = dataType(s.ColumnDefinition())
+		op.Definition = originalText(s.ColumnDefinition())
+		op.After = uidText(s.Uid(1))
+		op.First = s.FIRST() != nil
+	case *AlterByChangeColumnContext:
+		op.Kind = AlterChangeColumn
+		op.Column = uidText(s.GetOldColumn())
+		op.NewColumn = uidText(s.GetNewColumn())
+		op.Type = dataType(s.ColumnDefinition())
+		op.Definition = originalText(s.ColumnDefinition())
+		op.After = uidText(s.GetAfterColumn())
+		op.First = s.FIRST() != nil
+	case *AlterByRenameColumnContext:
+		op.Kind = AlterRenameColumn
+		op.Column = uidText(s.GetOldColumn())
+		op.NewColumn = uidText(s.GetNewColumn())
+	case *AlterByChangeDefaultContext:
+		op.Kind = AlterColumnDefault
+		op.Column = uidText(s.Uid())
+		if s.DefaultValue() != nil {
+			op.Definition = originalText(s.DefaultValue())
+		}
+	case *AlterByAddIndexContext:
+		op.Kind, op.IndexKind = AlterAddIndex, IndexNormal
+		op.Index = uidText(s.Uid())
+		op.Columns = indexColumns(s.IndexColumnNames())
+	case *AlterByAddUniqueKeyContext:
+		op.Kind, op.IndexKind = AlterAddIndex, IndexUnique
+		op.Index = uidText(s.GetIndexName())
+		op.Columns = indexColumns(s.IndexColumnNames())
+	case *AlterByAddSpecialIndexContext:
+		op.Kind, op.IndexKind = AlterAddIndex, IndexFulltext
+		if s.SPATIAL() != nil {
+			op.IndexKind = IndexSpatial
+		}
+		op.Index = uidText(s.Uid())
+		op.Columns = indexColumns(s.IndexColumnNames())
+	case *AlterByAddPrimaryKeyContext:
+		op.Kind, op.IndexKind = AlterAddIndex, IndexPrimary
+		op.Index = IndexPrimary
+		op.Columns = indexColumns(s.IndexColumnNames())
+	case *AlterByDropIndexContext:
+		op.Kind = AlterDropIndex
+		op.Index = uidText(s.Uid())
+	case *AlterByDropPrimaryKeyContext:
+		op.Kind = AlterDropIndex
+		op.Index = IndexPrimary
+	case *AlterByRenameIndexContext:
+		op.Kind = AlterRenameIndex
+		op.Index = uidText(s.Uid(0))
+		op.NewName = uidText(s.Uid(1))
+	case *AlterByRenameContext:
+		op.Kind = AlterRenameTable
+		if s.Uid() != nil {
+			op.NewName = uidText(s.Uid())
+		} else if s.FullId() != nil {
+			op.NewName = unquoteName(s.FullId().GetText())
+		}
+	}
+	return []AlterOperation{op}
+}
+
+func dataType(def IColumnDefinitionContext) string {
+	d, ok := def.(*ColumnDefinitionContext)
+	if !ok || d.DataType() == nil {
+		return ""
+	}
+	return originalText(d.DataType())
+}
+
+func uidText(uid IUidContext) string {
+	if uid == nil {
+		return ""
+	}
+	return unquoteName(uid.GetText())
+}
+
+// unquoteName 去掉标识符各段的反引号，如 `db`.`t` 返回 db.t
+func unquoteName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(part, "`")
+	}
+	return strings.Join(parts, ".")
+}
+
+// originalText 返回语法节点覆盖的 SQL 原文（含其中的空白）
+func originalText(ctx antlr.ParserRuleContext) string {
+	return tokenText(ctx.GetStart(), ctx.GetStop())
+}
+
+// tokenText 返回从 start 到 stop 两个词法单元之间（含）的 SQL 原文
+func tokenText(start, stop antlr.Token) string {
+	if start == nil || stop == nil || stop.GetStop() < start.GetStart() {
+		return ""
+	}
+	return start.GetInputStream().GetTextFromInterval(antlr.NewInterval(start.GetStart(), stop.GetStop()))
+}
@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAlter(t *testing.T) {
+	cases := []struct {
+		sql    string
+		table  string
+		ops    []AlterOperation
+		online bool
+	}{
+		{
+			sql:   "ALTER TABLE `db`.`t` ADD COLUMN c varchar(64) NOT NULL DEFAULT 'x'",
+			table: "db.t",
+			ops: []AlterOperation{
+				{Kind: AlterAddColumn, Column: "c", Type: "varchar(64)", Definition: "varchar(64) NOT NULL DEFAULT 'x'"},
+			},
+			online: true,
+		},
+		{
+			sql:   "ALTER TABLE t ADD COLUMN c int AFTER b, DROP COLUMN d",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterAddColumn, Column: "c", Type: "int", Definition: "int", After: "b"},
+				{Kind: AlterDropColumn, Column: "d"},
+			},
+		},
+		{
+			sql:   "ALTER TABLE t ADD COLUMN (a int, b text)",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterAddColumn, Column: "a", Type: "int", Definition: "int"},
+				{Kind: AlterAddColumn, Column: "b", Type: "text", Definition: "text"},
+			},
+			online: true,
+		},
+		{
+			sql:   "ALTER TABLE t MODIFY COLUMN a bigint FIRST, CHANGE b c int",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterModifyColumn, Column: "a", Type: "bigint", Definition: "bigint", First: true},
+				{Kind: AlterChangeColumn, Column: "b", NewColumn: "c", Type: "int", Definition: "int"},
+			},
+		},
+		{
+			sql:   "ALTER TABLE t RENAME COLUMN a TO b, ALTER COLUMN c SET DEFAULT 1, RENAME INDEX i1 TO i2",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterRenameColumn, Column: "a", NewColumn: "b"},
+				{Kind: AlterColumnDefault, Column: "c", Definition: "1"},
+				{Kind: AlterRenameIndex, Index: "i1", NewName: "i2"},
+			},
+			online: true,
+		},
+		{
+			sql:   "ALTER TABLE t ADD INDEX idx_a (a, b), ADD UNIQUE KEY uk (c), DROP INDEX idx_old",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterAddIndex, Index: "idx_a", IndexKind: IndexNormal, Columns: []string{"a", "b"}},
+				{Kind: AlterAddIndex, Index: "uk", IndexKind: IndexUnique, Columns: []string{"c"}},
+				{Kind: AlterDropIndex, Index: "idx_old"},
+			},
+			online: true,
+		},
+		{
+			sql:   "ALTER TABLE t DROP PRIMARY KEY, ADD PRIMARY KEY (id)",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterDropIndex, Index: IndexPrimary},
+				{Kind: AlterAddIndex, Index: IndexPrimary, IndexKind: IndexPrimary, Columns: []string{"id"}},
+			},
+		},
+		{
+			sql:   "ALTER TABLE t RENAME TO t2",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterRenameTable, NewName: "t2"},
+			},
+			online: true,
+		},
+		{
+			sql:   "ALTER TABLE t ENGINE = InnoDB",
+			table: "t",
+			ops: []AlterOperation{
+				{Kind: AlterOther},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			tables, err := ParseAlter(c.sql)
+			if err != nil {
+				t.Fatalf("ParseAlter: %v", err)
+			}
+			if len(tables) != 1 {
+				t.Fatalf("got %d statements", len(tables))
+			}
+			table := tables[0]
+			if table.Table != c.table {
+				t.Errorf("Table = %q, want %q", table.Table, c.table)
+			}
+			if len(table.Operations) != len(c.ops) {
+				t.Fatalf("got %d operations: %+v", len(table.Operations), table.Operations)
+			}
+			for i, op := range table.Operations {
+				want := c.ops[i]
+				if want.Columns == nil {
+					want.Columns = []string{}
+				}
+				// Text 是操作原文，不逐一比较
+				op.Text = ""
+				if !reflect.DeepEqual(op, want) {
+					t.Errorf("operation %d = %+v, want %+v", i, op, want)
+				}
+			}
+			if table.IsOnlineSafe() != c.online {
+				t.Errorf("IsOnlineSafe = %v, want %v", table.IsOnlineSafe(), c.online)
+			}
+		})
+	}
+}
+
+func TestParseAlterIgnoresOtherStatements(t *testing.T) {
+	tables, err := ParseAlter("SELECT 1; ALTER TABLE a DROP COLUMN x; ALTER TABLE b DROP COLUMN y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0].Table != "a" || tables[1].Table != "b" {
+		t.Errorf("got %+v", tables)
+	}
+	if _, err := ParseAlter("ALTER TABLE t ADD"); err == nil {
+		t.Errorf("expected a syntax error")
+	}
+}
+
+func TestParseSQLAlter(t *testing.T) {
+	result, err := ParseSQL("ALTER TABLE t ADD COLUMN c int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatementType != StatementAlterTable || result.Alter == nil || result.Alter.Table != "t" {
+		t.Errorf("got %q %+v", result.StatementType, result.Alter)
+	}
+}
@@ -0,0 +1,41 @@
+// Package analyze 保留原有的 Analyze 入口，实现已移到 parser.ParseSQL。
+//
+// Deprecated: 新代码直接使用 github.com/AIntelligenceGame/bus/parser 包的 ParseSQL。
+package analyze
+
+import (
+	"github.com/AIntelligenceGame/bus/parser"
+)
+
+// 结果类型与 parser 包相同，SqlParseResult 中出现的类型都有对应的别名
+type (
+	SqlParseResult  = parser.SqlParseResult
+	ColumnInfo      = parser.ColumnInfo
+	GroupByInfo     = parser.GroupByInfo
+	OrderByInfo     = parser.OrderByInfo
+	LimitInfo       = parser.LimitInfo
+	JoinInfo        = parser.JoinInfo
+	PredicateInfo   = parser.PredicateInfo
+	SubQueryInfo    = parser.SubQueryInfo
+	WithInfo        = parser.WithInfo
+	UnionInfo       = parser.UnionInfo
+	InsertInfo      = parser.InsertInfo
+	UpdateInfo      = parser.UpdateInfo
+	Assignment      = parser.Assignment
+	DeleteInfo      = parser.DeleteInfo
+	CreateTableInfo = parser.CreateTableInfo
+	ColumnDef       = parser.ColumnDef
+	IndexDef        = parser.IndexDef
+	AlterTable      = parser.AlterTable
+	AlterOperation  = parser.AlterOperation
+	Explain         = parser.Explain
+	ParseError      = parser.ParseError
+	Directive       = parser.Directive
+)
+
+// Analyze 同 parser.ParseSQL
+//
+// Deprecated: 使用 parser.ParseSQL。
+func Analyze(sql string) (*SqlParseResult, error) {
+	return parser.ParseSQL(sql)
+}
@@ -0,0 +1,208 @@
+package parser
+
+import "strings"
+
+// CREATE TABLE 的形式
+const (
+	CreateColumns = "COLUMNS" // CREATE TABLE t (...)
+	CreateLike    = "LIKE"    // CREATE TABLE t LIKE s
+	CreateSelect  = "SELECT"  // CREATE TABLE t [(...)] AS SELECT ...
+)
+
+// 索引类型
+const (
+	IndexNormal   = "INDEX"
+	IndexUnique   = "UNIQUE"
+	IndexFulltext = "FULLTEXT"
+	IndexSpatial  = "SPATIAL"
+)
+
+// CreateTableInfo CREATE TABLE 语句
+type CreateTableInfo struct {
+	Table       string      `json:"table"` // 去掉反引号，默认小写（见 ParseOptions）
+	Kind        string      `json:"kind"`  // 见 Create* 常量
+	Like        string      `json:"like"`  // CREATE TABLE ... LIKE 的源表
+	Temporary   bool        `json:"temporary"`
+	IfNotExists bool        `json:"ifNotExists"`
+	Columns     []ColumnDef `json:"columns"`
+	PrimaryKey  []string    `json:"primaryKey"` // 主键列，列定义中的 PRIMARY KEY 也计入
+	Indexes     []IndexDef  `json:"indexes"`    // 主键以外的索引，列定义中的 UNIQUE 也计入
+
+	Engine  string   `json:"engine"`
+	Charset string   `json:"charset"`
+	Collate string   `json:"collate"`
+	Comment string   `json:"comment"`
+	Options []string `json:"options"` // 全部表选项的原文
+}
+
+// ColumnDef 建表语句中的一列
+type ColumnDef struct {
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`          // 数据类型原文，如 varchar(64)
+	Nullable      bool    `json:"nullable"`      // 没有 NOT NULL 且不是主键时为 true
+	Default       *string `json:"default"`       // DEFAULT 的原文，没有时为 nil
+	AutoIncrement bool    `json:"autoIncrement"` // 是否为 AUTO_INCREMENT
+	Comment       string  `json:"comment"`
+}
+
+// IndexDef 建表语句中的一个索引
+type IndexDef struct {
+	Name    string   `json:"name"` // 没有指定名称时为空
+	Kind    string   `json:"kind"` // 见 Index* 常量
+	Columns []string `json:"columns"`
+}
+
+func (l *sqlListener) EnterColumnCreateTable(ctx *ColumnCreateTableContext) {
+	create := newCreateTable(ctx.TableName(), l.fold, CreateColumns, ctx.TEMPORARY() != nil, ctx.IfNotExists() != nil)
+	create.addDefinitions(ctx.CreateDefinitions())
+	for _, opt := range ctx.AllTableOption() {
+		create.addOption(opt)
+	}
+	l.result.CreateTable = create
+}
+
+func (l *sqlListener) EnterAlterTable(ctx *AlterTableContext) {
+	if l.result.Alter == nil {
+		alter := alterTable(ctx)
+		l.result.Alter = &alter
+	}
+}
+
+func (l *sqlListener) EnterCopyCreateTable(ctx *CopyCreateTableContext) {
+	create := newCreateTable(ctx.TableName(0), l.fold, CreateLike, ctx.TEMPORARY() != nil, ctx.IfNotExists() != nil)
+	create.Like = l.fold(unquoteName(ctx.TableName(1).GetText()))
+	l.result.CreateTable = create
+}
+
+func (l *sqlListener) EnterQueryCreateTable(ctx *QueryCreateTableContext) {
+	create := newCreateTable(ctx.TableName(), l.fold, CreateSelect, ctx.TEMPORARY() != nil, ctx.IfNotExists() != nil)
+	create.addDefinitions(ctx.CreateDefinitions())
+	for _, opt := range ctx.AllTableOption() {
+		create.addOption(opt)
+	}
+	l.result.CreateTable = create
+}
+
+func newCreateTable(name ITableNameContext, fold func(string) string, kind string, temporary, ifNotExists bool) *CreateTableInfo {
+	return &CreateTableInfo{
+		Table:       fold(unquoteName(name.GetText())),
+		Kind:        kind,
+		Temporary:   temporary,
+		IfNotExists: ifNotExists,
+		Columns:     []ColumnDef{},
+		PrimaryKey:  []string{},
+		Indexes:     []IndexDef{},
+		Options:     []string{},
+	}
+}
+
+func (c *CreateTableInfo) addDefinitions(defs ICreateDefinitionsContext) {
+	list, ok := defs.(*CreateDefinitionsContext)
+	if !ok {
+		return
+	}
+	for _, def := range list.AllCreateDefinition() {
+		switch d := def.(type) {
+		case *ColumnDeclarationContext:
+			c.addColumn(uidText(d.Uid()), d.ColumnDefinition().(*ColumnDefinitionContext))
+		case *ConstraintDeclarationContext:
+			switch t := d.TableConstraint().(type) {
+			case *PrimaryKeyTableConstraintContext:
+				c.PrimaryKey = append(c.PrimaryKey, indexColumns(t.IndexColumnNames())...)
+			case *UniqueKeyTableConstraintContext:
+				name := t.GetIndex()
+				if name == nil {
+					name = t.GetName()
+				}
+				c.Indexes = append(c.Indexes, IndexDef{Name: uidText(name), Kind: IndexUnique, Columns: indexColumns(t.IndexColumnNames())})
+			}
+		case *IndexDeclarationContext:
+			switch i := d.IndexColumnDefinition().(type) {
+			case *SimpleIndexDeclarationContext:
+				c.Indexes = append(c.Indexes, IndexDef{Name: uidText(i.Uid()), Kind: IndexNormal, Columns: indexColumns(i.IndexColumnNames())})
+			case *SpecialIndexDeclarationContext:
+				kind := IndexFulltext
+				if i.SPATIAL() != nil {
+					kind = IndexSpatial
+				}
+				c.Indexes = append(c.Indexes, IndexDef{Name: uidText(i.Uid()), Kind: kind, Columns: indexColumns(i.IndexColumnNames())})
+			}
+		}
+	}
+	// 主键列隐含 NOT NULL
+	for i := range c.Columns {
+		for _, pk := range c.PrimaryKey {
+			if strings.EqualFold(c.Columns[i].Name, pk) {
+				c.Columns[i].Nullable = false
+			}
+		}
+	}
+}
+
+func (c *CreateTableInfo) addColumn(name string, def *ColumnDefinitionContext) {
+	col := ColumnDef{Name: name, Type: originalText(def.DataType()), Nullable: true}
+	for _, constraint := range def.AllColumnConstraint() {
+		switch k := constraint.(type) {
+		case *NullColumnConstraintContext:
+			col.Nullable = k.NullNotnull().(*NullNotnullContext).NOT() == nil
+		case *DefaultColumnConstraintContext:
+			value := originalText(k.DefaultValue())
+			col.Default = &value
+		case *AutoIncrementColumnConstraintContext:
+			col.AutoIncrement = k.AUTO_INCREMENT() != nil
+		case *CommentColumnConstraintContext:
+			col.Comment = unquoteString(k.STRING_LITERAL().GetText())
+		case *PrimaryKeyColumnConstraintContext:
+			c.PrimaryKey = append(c.PrimaryKey, name)
+		case *UniqueKeyColumnConstraintContext:
+			c.Indexes = append(c.Indexes, IndexDef{Kind: IndexUnique, Columns: []string{name}})
+		}
+	}
+	c.Columns = append(c.Columns, col)
+}
+
+func (c *CreateTableInfo) addOption(opt ITableOptionContext) {
+	c.Options = append(c.Options, originalText(opt))
+	switch o := opt.(type) {
+	case *TableOptionEngineContext:
+		if o.EngineName() != nil {
+			c.Engine = unquoteName(o.EngineName().GetText())
+		}
+	case *TableOptionCharsetContext:
+		c.Charset = unquoteName(o.CharsetName().GetText())
+	case *TableOptionCollateContext:
+		c.Collate = unquoteName(o.CollationName().GetText())
+	case *TableOptionCommentContext:
+		c.Comment = unquoteString(o.STRING_LITERAL().GetText())
+	}
+}
+
+// indexColumns 索引中的列名，函数索引取表达式原文
+func indexColumns(names IIndexColumnNamesContext) []string {
+	columns := []string{}
+	list, ok := names.(*IndexColumnNamesContext)
+	if !ok {
+		return columns
+	}
+	for _, n := range list.AllIndexColumnName() {
+		col := n.(*IndexColumnNameContext)
+		switch {
+		case col.Uid() != nil:
+			columns = append(columns, uidText(col.Uid()))
+		case col.STRING_LITERAL() != nil:
+			columns = append(columns, unquoteString(col.STRING_LITERAL().GetText()))
+		default:
+			columns = append(columns, originalText(col))
+		}
+	}
+	return columns
+}
+
+// unquoteString 去掉字符串字面量两端的引号，并把连续两个引号还原为一个
+func unquoteString(s string) string {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return s
+	}
+	q := string(s[0])
+	return strings.ReplaceAll(s[1:len(s)-1], q+q, q)
+}
@@ -0,0 +1,61 @@
+package parser
+
+// DeleteInfo DELETE 语句
+type DeleteInfo struct {
+	Table    string        `json:"table"`    // 目标表，多表 DELETE 时为第一个目标；去掉反引号，默认小写（见 ParseOptions）
+	Alias    string        `json:"alias"`    // 多表 DELETE 中目标使用的别名，没有时为空
+	Tables   []string      `json:"tables"`   // 全部目标表，别名已解析为表名
+	Where    string        `json:"where"`    // WHERE 条件原文，拆分后的条件见 SqlParseResult.Where
+	HasWhere bool          `json:"hasWhere"` // 是否有 WHERE，没有时会删除全部数据
+	OrderBy  []OrderByInfo `json:"orderBy"`  // 单表 DELETE 的 ORDER BY
+	Limit    *LimitInfo    `json:"limit"`    // 单表 DELETE 的 LIMIT，没有时为 nil
+}
+
+// DELETE 语句与最外层查询同级，其中的子查询深度从 2 开始
+func (l *sqlListener) EnterSingleDeleteStatement(ctx *SingleDeleteStatementContext) {
+	l.depth++
+	source := singleSource(ctx.TableName(), nil, l.fold)
+	del := &DeleteInfo{Table: source.table, Tables: []string{source.table}, OrderBy: []OrderByInfo{}}
+	if order, ok := ctx.OrderByClause().(*OrderByClauseContext); ok {
+		del.OrderBy = orderByItems(order)
+	}
+	if ctx.LIMIT() != nil {
+		del.Limit = limitAfter(ctx.GetParser().GetTokenStream(), ctx.LIMIT())
+		l.result.Limit = del.Limit
+	}
+	l.addDelete(del, ctx.Expression())
+}
+
+func (l *sqlListener) ExitSingleDeleteStatement(ctx *SingleDeleteStatementContext) { l.depth-- }
+
+func (l *sqlListener) EnterMultipleDeleteStatement(ctx *MultipleDeleteStatementContext) {
+	l.depth++
+	sources := collectSources(ctx.TableSources(), nil, l.fold)
+	del := &DeleteInfo{Tables: []string{}, OrderBy: []OrderByInfo{}}
+	for i, name := range ctx.AllTableName() {
+		target := l.fold(unquoteName(name.GetText()))
+		table := target
+		if s, ok := resolveTable(target, sources, l.fold); ok && s.table != "" {
+			table = s.table
+		}
+		if i == 0 {
+			del.Table = table
+			if table != target {
+				del.Alias = target
+			}
+		}
+		del.Tables = appendUnique(del.Tables, table)
+	}
+	l.addDelete(del, ctx.Expression())
+}
+
+func (l *sqlListener) ExitMultipleDeleteStatement(ctx *MultipleDeleteStatementContext) { l.depth-- }
+
+func (l *sqlListener) addDelete(del *DeleteInfo, where IExpressionContext) {
+	if where != nil {
+		del.Where = originalText(where)
+		del.HasWhere = true
+		l.setWhere(where)
+	}
+	l.result.Delete = del
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// 注释中的忽略指令，供 lint 层跳过指定规则。
+//
+// 格式（不区分大小写，作用于整条语句，ParseSQL 的结果见 SqlParseResult.Directives）：
+//
+//	/* noqa */                  忽略全部规则
+//	/* noqa: cartesian */       忽略指定规则，多个规则用逗号或空白分隔，块注释中可以换行
+//	-- noqa: cartesian, in-list
+//	# noqa: in-list
+//
+// 可识别的规则名见 Rule* 常量，未识别的规则名也会保留在 Directive.Rules 中，由 lint 层决定如何处理。
+
+// 可识别的规则名
+const (
+	RuleCartesian = "cartesian"  // 缺少连接条件的笛卡尔积
+	RuleCrossJoin = "cross-join" // 显式的 CROSS JOIN
+	RuleInList    = "in-list"    // 元素过多的 IN 列表 / OR 链
+)
+
+// KnownRules 全部可识别的规则名
+var KnownRules = []string{RuleCartesian, RuleCrossJoin, RuleInList}
+
+var directivePattern = regexp.MustCompile(`(?is)^noqa\b\s*(?::\s*(.*))?$`)
+
+// Directive 一条忽略指令
+type Directive struct {
+	Rules []string `json:"rules"` // 忽略的规则，为空表示忽略全部规则
+	Text  string   `json:"text"`  // 注释原文
+	Line  int      `json:"line"`  // 注释所在行号
+	Start int      `json:"start"` // 注释在原始 SQL 中的起始字节偏移
+	Stop  int      `json:"stop"`  // 注释在原始 SQL 中的结束字节偏移（不含）
+}
+
+// Suppresses 判断该指令是否忽略 rule
+func (d Directive) Suppresses(rule string) bool {
+	if len(d.Rules) == 0 {
+		return true
+	}
+	for _, r := range d.Rules {
+		if r == strings.ToLower(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suppressed 判断一组指令中是否有忽略 rule 的
+func Suppressed(directives []Directive, rule string) bool {
+	for _, d := range directives {
+		if d.Suppresses(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDirectives 只做词法分析，从注释中提取忽略指令，SQL 有语法错误时同样可用
+func ParseDirectives(sql string) []Directive {
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	lexer.RemoveErrorListeners()
+	return collectDirectives(sql, lexer.GetAllTokens())
+}
+
+// collectDirectives 从 sql 的词法单元中提取忽略指令
+func collectDirectives(sql string, tokens []antlr.Token) []Directive {
+	offsets := byteOffsets(sql)
+	directives := make([]Directive, 0)
+	for _, t := range tokens {
+		if t.GetTokenType() == antlr.TokenEOF {
+			continue
+		}
+		start, stop := offsets[t.GetStart()], offsets[t.GetStop()+1]
+		if d, ok := parseDirective(t.GetTokenType(), sql[start:stop]); ok {
+			d.Line, d.Start, d.Stop = t.GetLine(), start, stop
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// parseDirective 解析一个注释词法单元
+func parseDirective(tokenType int, text string) (Directive, bool) {
+	body := text
+	switch tokenType {
+	case MySqlLexerCOMMENT_INPUT:
+		body = strings.TrimSuffix(strings.TrimPrefix(body, "/*"), "*/")
+	case MySqlLexerLINE_COMMENT:
+		body = strings.TrimPrefix(strings.TrimPrefix(body, "--"), "#")
+	default:
+		return Directive{}, false
+	}
+	m := directivePattern.FindStringSubmatch(strings.TrimSpace(body))
+	if m == nil {
+		return Directive{}, false
+	}
+	rules := make([]string, 0)
+	for _, r := range strings.FieldsFunc(m[1], func(c rune) bool { return c == ',' || unicode.IsSpace(c) }) {
+		rules = append(rules, strings.ToLower(r))
+	}
+	return Directive{Rules: rules, Text: text}, true
+}
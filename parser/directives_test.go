@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDirectives(t *testing.T) {
+	cases := []struct {
+		sql   string
+		rules [][]string // 每条指令的规则，nil 表示没有指令
+	}{
+		{"SELECT * FROM a, b /* noqa */", [][]string{{}}},
+		{"SELECT * FROM a, b /* NOQA: Cartesian */", [][]string{{"cartesian"}}},
+		{"SELECT * FROM a -- noqa: cartesian, in-list\n", [][]string{{"cartesian", "in-list"}}},
+		{"SELECT * FROM a # noqa:in-list cross-join", [][]string{{"in-list", "cross-join"}}},
+		{"SELECT * FROM a /* noqa:\n  cartesian,\n  in-list\n*/", [][]string{{"cartesian", "in-list"}}},
+		{"/* noqa: cartesian */ SELECT * FROM a /* noqa: in-list */", [][]string{{"cartesian"}, {"in-list"}}},
+		{"SELECT * FROM a /* noqanot */ -- not noqa", nil},
+		{"SELECT '/* noqa */' FROM a", nil},
+		{"SELECT * FROM WHERE /* noqa */", [][]string{{}}},
+	}
+	for _, c := range cases {
+		var rules [][]string
+		for _, d := range ParseDirectives(c.sql) {
+			if c.sql[d.Start:d.Stop] != d.Text {
+				t.Errorf("%q: Text = %q, offsets %d-%d", c.sql, d.Text, d.Start, d.Stop)
+			}
+			rules = append(rules, d.Rules)
+		}
+		if !reflect.DeepEqual(rules, c.rules) {
+			t.Errorf("ParseDirectives(%q) rules = %q, want %q", c.sql, rules, c.rules)
+		}
+	}
+}
+
+func TestSuppresses(t *testing.T) {
+	all := Directive{Rules: []string{}}
+	some := Directive{Rules: []string{RuleCartesian, "custom"}}
+	cases := []struct {
+		d    Directive
+		rule string
+		want bool
+	}{
+		{all, RuleInList, true},
+		{all, "anything", true},
+		{some, RuleCartesian, true},
+		{some, "CARTESIAN", true},
+		{some, "custom", true},
+		{some, RuleInList, false},
+	}
+	for _, c := range cases {
+		if got := c.d.Suppresses(c.rule); got != c.want {
+			t.Errorf("%+v.Suppresses(%q) = %v, want %v", c.d, c.rule, got, c.want)
+		}
+	}
+	if Suppressed(nil, RuleCartesian) || !Suppressed([]Directive{{Rules: []string{RuleInList}}, some}, RuleCartesian) {
+		t.Error("Suppressed")
+	}
+}
+
+func TestParseSQLDirectives(t *testing.T) {
+	sql := "SELECT * FROM a, b /* noqa: cartesian */ WHERE a.x IN (?, ?) -- noqa: in-list"
+	result := mustParse(t, sql)
+	if len(result.Directives) != 2 || !Suppressed(result.Directives, RuleCartesian) || !Suppressed(result.Directives, RuleInList) || Suppressed(result.Directives, RuleCrossJoin) {
+		t.Errorf("Directives = %+v", result.Directives)
+	}
+	if !reflect.DeepEqual(result.Directives, ParseDirectives(sql)) {
+		t.Errorf("Directives = %+v, want %+v", result.Directives, ParseDirectives(sql))
+	}
+
+	// EXPLAIN 前缀之前的注释也计入，偏移相对于完整的 SQL
+	sql = "/* noqa */ EXPLAIN FORMAT=JSON SELECT * FROM a /* noqa: in-list */"
+	result = mustParse(t, sql)
+	if !reflect.DeepEqual(result.Directives, ParseDirectives(sql)) || len(result.Directives) != 2 {
+		t.Errorf("EXPLAIN Directives = %+v", result.Directives)
+	}
+
+	if result := mustParse(t, "SELECT 1"); result.Directives == nil || len(result.Directives) != 0 {
+		t.Errorf("Directives = %#v, want empty", result.Directives)
+	}
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 源文件编码
+const (
+	EncodingUTF8    = "utf8" // 默认
+	EncodingGB18030 = "gb18030"
+	EncodingGBK     = "gbk"
+)
+
+// utf8BOM UTF-8 的字节顺序标记
+const utf8BOM = "\ufeff"
+
+// DecodeOptions Decode 的选项
+type DecodeOptions struct {
+	SourceEncoding string // 源编码，见 Encoding* 常量，为空时按 UTF-8 处理
+	ReplaceInvalid bool   // 为 true 时非法字节替换为 U+FFFD，否则返回 *InvalidEncodingError
+}
+
+// InvalidEncodingError 源字节不是合法的指定编码
+type InvalidEncodingError struct {
+	Encoding string
+	Offset   int // 第一个非法字节在源字节中的偏移
+}
+
+func (e *InvalidEncodingError) Error() string {
+	return fmt.Sprintf("invalid %v byte at offset %d", e.Encoding, e.Offset)
+}
+
+// Source 解码后的 SQL 及其与源字节的位置对应关系
+type Source struct {
+	SQL     string
+	offsets []int // SQL 中每个字节对应的源字节偏移，多出的最后一项为源字节长度
+}
+
+// SourceOffset 把 SQL 中的字节偏移（如 Token.Start）换算为源字节中的偏移
+func (s *Source) SourceOffset(i int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= len(s.offsets) {
+		return s.offsets[len(s.offsets)-1]
+	}
+	return s.offsets[i]
+}
+
+// Decode 把源字节转为 UTF-8 的 SQL：去掉开头的 BOM，按 SourceEncoding 转码并校验。
+// 结果可直接传给 ParseTokens 等入口，报告的位置经 SourceOffset 换算后对应源字节。
+func Decode(src []byte, opts DecodeOptions) (*Source, error) {
+	enc := strings.ToLower(opts.SourceEncoding)
+	var decoder *encoding.Decoder
+	switch enc {
+	case "", EncodingUTF8, "utf-8":
+		enc = EncodingUTF8
+	case EncodingGB18030:
+		decoder = simplifiedchinese.GB18030.NewDecoder()
+	case EncodingGBK:
+		decoder = simplifiedchinese.GBK.NewDecoder()
+	default:
+		return nil, fmt.Errorf("unsupported source encoding %v", opts.SourceEncoding)
+	}
+
+	pos := 0
+	if enc == EncodingUTF8 && bytes.HasPrefix(src, []byte(utf8BOM)) {
+		pos = len(utf8BOM)
+	}
+
+	var b strings.Builder
+	offsets := make([]int, 0, len(src)+1)
+	emit := func(s string, at int) {
+		b.WriteString(s)
+		for i := 0; i < len(s); i++ {
+			offsets = append(offsets, at)
+		}
+	}
+	for pos < len(src) {
+		var char string
+		size := 1
+		if decoder == nil {
+			r, n := utf8.DecodeRune(src[pos:])
+			size = n
+			if r != utf8.RuneError || n != 1 {
+				char = string(src[pos : pos+n])
+			}
+		} else {
+			size = gbCharLen(src[pos:], enc)
+			if out, err := decoder.Bytes(src[pos : pos+size]); err == nil && !strings.ContainsRune(string(out), utf8.RuneError) {
+				char = string(out)
+			}
+		}
+		if char == "" {
+			if !opts.ReplaceInvalid {
+				return nil, &InvalidEncodingError{Encoding: enc, Offset: pos}
+			}
+			char = string(utf8.RuneError)
+		}
+		emit(char, pos)
+		pos += size
+	}
+	return &Source{SQL: b.String(), offsets: append(offsets, len(src))}, nil
+}
+
+// gbCharLen 返回 GBK/GB18030 下一个字符占用的字节数
+func gbCharLen(p []byte, enc string) int {
+	if p[0] < 0x80 || len(p) == 1 {
+		return 1
+	}
+	// GB18030 四字节字符的第二个字节为 0x30-0x39
+	if enc == EncodingGB18030 && p[1] >= 0x30 && p[1] <= 0x39 && len(p) >= 4 {
+		return 4
+	}
+	return 2
+}
+
+// decodeSQL 把 encoding 编码的 sql 转为 UTF-8，encoding 为空或 UTF-8 时只做校验。
+// 与 Decode 不同，UTF-8 开头的 BOM 保留给 lexInput 处理，报告的位置不变
+func decodeSQL(sql, encoding string) (string, error) {
+	switch strings.ToLower(encoding) {
+	case "", EncodingUTF8, "utf-8":
+		for i, r := range sql {
+			if r == utf8.RuneError {
+				if _, n := utf8.DecodeRuneInString(sql[i:]); n == 1 {
+					return "", &InvalidEncodingError{Encoding: EncodingUTF8, Offset: i}
+				}
+			}
+		}
+		return sql, nil
+	}
+	src, err := Decode([]byte(sql), DecodeOptions{SourceEncoding: encoding})
+	if err != nil {
+		return "", err
+	}
+	return src.SQL, nil
+}
+
+// lexInput 返回交给词法器的输入：开头的 BOM 替换为空格，字符下标保持不变
+func lexInput(sql string) string {
+	if strings.HasPrefix(sql, utf8BOM) {
+		return " " + sql[len(utf8BOM):]
+	}
+	return sql
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		opts    DecodeOptions
+		want    string
+		invalid int // 期望的非法字节偏移，-1 表示合法
+	}{
+		{"UTF-8", "SELECT '中' FROM t", DecodeOptions{}, "SELECT '中' FROM t", -1},
+		{"去掉 BOM", "\xef\xbb\xbfSELECT 1", DecodeOptions{}, "SELECT 1", -1},
+		{"非法 UTF-8", "SELECT '\xff'", DecodeOptions{}, "", 8},
+		{"替换非法字节", "SELECT '\xff'", DecodeOptions{ReplaceInvalid: true}, "SELECT '�'", -1},
+		{"GBK", "SELECT '\xd6\xd0'", DecodeOptions{SourceEncoding: EncodingGBK}, "SELECT '中'", -1},
+		{"GB18030 四字节", "SELECT '\x81\x30\x81\x30'", DecodeOptions{SourceEncoding: EncodingGB18030}, "SELECT '\u0080'", -1},
+		{"非法 GBK", "SELECT '\xd6'", DecodeOptions{SourceEncoding: EncodingGBK}, "", 8},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			src, err := Decode([]byte(c.src), c.opts)
+			if c.invalid >= 0 {
+				var e *InvalidEncodingError
+				if !errors.As(err, &e) || e.Offset != c.invalid {
+					t.Fatalf("err = %v, want invalid byte at %d", err, c.invalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src.SQL != c.want {
+				t.Errorf("SQL = %q, want %q", src.SQL, c.want)
+			}
+		})
+	}
+	if _, err := Decode([]byte("x"), DecodeOptions{SourceEncoding: "latin1"}); err == nil {
+		t.Errorf("unsupported encoding should fail")
+	}
+}
+
+func TestSourceOffset(t *testing.T) {
+	// GBK 的 中 占 2 字节，转为 UTF-8 后占 3 字节
+	src, err := Decode([]byte("\xd6\xd0 t"), DecodeOptions{SourceEncoding: EncodingGBK})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range map[int]int{0: 0, 2: 0, 3: 2, 4: 3, 100: 4, -1: 0} {
+		if got := src.SourceOffset(i); got != want {
+			t.Errorf("SourceOffset(%d) = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	cases := []struct {
+		name    string
+		sql     string
+		opts    ParseOptions
+		column  string
+		invalid bool
+	}{
+		{"UTF-8", "SELECT 中文 FROM t", ParseOptions{}, "中文", false},
+		{"BOM", "\ufeffSELECT a FROM t", ParseOptions{}, "a", false},
+		{"GBK", "SELECT \xd6\xd0\xce\xc4 FROM t", ParseOptions{Encoding: EncodingGBK}, "中文", false},
+		{"EXPLAIN GBK", "EXPLAIN SELECT \xd6\xd0\xce\xc4 FROM t", ParseOptions{Encoding: EncodingGBK}, "中文", false},
+		{"EXPLAIN GBK 字符串", "EXPLAIN SELECT a FROM t WHERE b = '\xd6\xd0\xce\xc4'", ParseOptions{Encoding: EncodingGBK}, "a", false},
+		{"非法 UTF-8", "SELECT \xd6\xd0\xce\xc4 FROM t", ParseOptions{}, "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := ParseSQLWith(c.sql, c.opts)
+			if c.invalid {
+				var e *InvalidEncodingError
+				if !errors.As(err, &e) {
+					t.Fatalf("err = %v, want *InvalidEncodingError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Columns) != 1 || result.Columns[0].Name != c.column {
+				t.Errorf("Columns = %+v, want %q", result.Columns, c.column)
+			}
+		})
+	}
+
+	var e *InvalidEncodingError
+	if _, err := ParseTokens("SELECT '\xff'"); !errors.As(err, &e) {
+		t.Errorf("ParseTokens err = %v, want *InvalidEncodingError", err)
+	}
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// EquivalentOptions Equivalent 的可选等价规则
+type EquivalentOptions struct {
+	// AndOrder 为 true 时 AND 连接的条件不区分顺序，WHERE a=1 AND b=2 与 WHERE b=2 AND a=1 视为相同。
+	// 按 MySQL 的优先级分组，a OR b AND c 中的 b AND c 同样不区分顺序；括号内部各自独立比较，
+	// 不改变 OR、XOR 连接的条件的顺序
+	AndOrder bool
+}
+
+// Equivalent 按默认规则比较两条 SQL 的结构是否相同，见 EquivalentWith
+func Equivalent(a, b string) (bool, error) {
+	return EquivalentWith(a, b, EquivalentOptions{})
+}
+
+// EquivalentWith 比较两条 SQL 规范化之后的语法树是否相同，任意一条有语法错误时返回 error。
+//
+// 视为相同的差异：
+//
+//	空白、注释、语句末尾的分号
+//	关键字大小写（SELECT 与 select）
+//	标识符的反引号（`a` 与 a）
+//	表别名的命名（FROM t x WHERE x.id=1 与 FROM t AS y WHERE y.id=1），按出现顺序对应
+//	opts.AndOrder 为 true 时 AND 条件的顺序
+//
+// 视为不同的差异：标识符和字符串的大小写、SELECT 列的顺序、列别名（会改变结果的列名）、
+// 多余的括号、数值的写法（1 与 1.0）以及其他任何语法树上的差异。
+// 比较是纯语法的，语义相同但写法不同的查询（如 IN 与 OR 展开）不视为相同。
+func EquivalentWith(a, b string, opts EquivalentOptions) (bool, error) {
+	na, err := normalizeSQL(a, opts)
+	if err != nil {
+		return false, fmt.Errorf("first sql: %v", err)
+	}
+	nb, err := normalizeSQL(b, opts)
+	if err != nil {
+		return false, fmt.Errorf("second sql: %v", err)
+	}
+	return na == nb, nil
+}
+
+// normalizeSQL 返回 sql 规范化后的文本形式
+func normalizeSQL(sql string, opts EquivalentOptions) (string, error) {
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := NewMySqlParser(stream)
+
+	errs := &syntaxErrorListener{}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errs)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errs)
+
+	tree := parseRoot(p)
+	if len(errs.errors) > 0 {
+		return "", errs.errors[0]
+	}
+
+	aliases := &aliasListener{names: map[string]string{}, defs: map[int]bool{}, as: map[int]bool{}}
+	antlr.ParseTreeWalkerDefault.Walk(aliases, tree)
+
+	n := &normalizer{
+		opts:    opts,
+		symbols: lexer.SymbolicNames,
+		aliases: aliases,
+		next:    map[int]int{},
+	}
+	prev := -1
+	for _, t := range stream.GetAllTokens() {
+		if t.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		if prev >= 0 {
+			n.next[prev] = t.GetTokenType()
+		}
+		prev = t.GetTokenIndex()
+	}
+
+	var parts []string
+	n.render(tree, &parts)
+	return strings.Join(parts, " "), nil
+}
+
+// aliasListener 按出现顺序收集表别名
+type aliasListener struct {
+	*BaseMySqlParserListener
+	names map[string]string // 别名 -> 占位名
+	defs  map[int]bool      // 定义别名的词法单元下标
+	as    map[int]bool      // 别名前 AS 的词法单元下标
+}
+
+func (l *aliasListener) add(alias IUidContext, as antlr.TerminalNode) {
+	if alias == nil {
+		return
+	}
+	if as != nil {
+		l.as[as.GetSymbol().GetTokenIndex()] = true
+	}
+	name := unquoteName(alias.GetText())
+	if _, ok := l.names[name]; !ok {
+		l.names[name] = fmt.Sprintf("$t%d", len(l.names)+1)
+	}
+	for i := alias.GetStart().GetTokenIndex(); i <= alias.GetStop().GetTokenIndex(); i++ {
+		l.defs[i] = true
+	}
+}
+
+func (l *aliasListener) EnterAtomTableItem(ctx *AtomTableItemContext) {
+	// 被误解析为别名的 LEFT/RIGHT 按原文输出，见 atomAlias
+	if alias, typ := atomAlias(ctx); alias != "" || typ == "" {
+		l.add(ctx.GetAlias(), ctx.AS())
+	}
+}
+
+func (l *aliasListener) EnterSubqueryTableItem(ctx *SubqueryTableItemContext) {
+	l.add(ctx.GetAlias(), ctx.AS())
+}
+
+type normalizer struct {
+	opts    EquivalentOptions
+	symbols []string
+	aliases *aliasListener
+	next    map[int]int // 词法单元下标 -> 下一个非隐藏词法单元的类型
+}
+
+func (n *normalizer) render(tree antlr.Tree, parts *[]string) {
+	switch node := tree.(type) {
+	case antlr.TerminalNode:
+		if text := n.token(node.GetSymbol()); text != "" {
+			*parts = append(*parts, text)
+		}
+		return
+	case *LogicalExpressionContext:
+		if n.opts.AndOrder && node.LogicalOperator() != nil {
+			*parts = append(*parts, n.renderChain(newLogicalChain(node)))
+			return
+		}
+	}
+	for i := 0; i < tree.GetChildCount(); i++ {
+		n.render(tree.GetChild(i), parts)
+	}
+}
+
+// renderChain 按 MySQL 的优先级输出一串逻辑条件，AND 连接的各组条件排序后输出
+func (n *normalizer) renderChain(c logicalChain) string {
+	op, groups := c.split()
+	if op == "" {
+		var sub []string
+		n.render(c.operands[0], &sub)
+		return strings.Join(sub, " ")
+	}
+	operands := make([]string, 0, len(groups))
+	for _, g := range groups {
+		operands = append(operands, n.renderChain(g))
+	}
+	if op == "AND" {
+		sort.Strings(operands)
+	}
+	return strings.Join(operands, " "+op+" ")
+}
+
+// token 返回词法单元规范化后的文本，分号和 EOF 返回空串
+func (n *normalizer) token(t antlr.Token) string {
+	switch t.GetTokenType() {
+	case antlr.TokenEOF, MySqlLexerSEMI:
+		return ""
+	case MySqlLexerAS:
+		// 表别名前的 AS 可以省略
+		if n.aliases.as[t.GetTokenIndex()] {
+			return ""
+		}
+	case MySqlLexerDOT_ID:
+		return "." + strings.Trim(t.GetText()[1:], "`")
+	}
+	text := t.GetText()
+	if len(text) >= 2 && strings.HasPrefix(text, "`") && strings.HasSuffix(text, "`") {
+		text = strings.ReplaceAll(text[1:len(text)-1], "``", "`")
+	}
+	// 表别名的定义和 alias.column 形式的引用替换为占位名
+	if placeholder, ok := n.aliases.names[text]; ok {
+		next := n.next[t.GetTokenIndex()]
+		if n.aliases.defs[t.GetTokenIndex()] || next == MySqlLexerDOT || next == MySqlLexerDOT_ID {
+			return placeholder
+		}
+	}
+	if t.GetTokenType() < len(n.symbols) && n.symbols[t.GetTokenType()] == strings.ToUpper(text) {
+		return strings.ToUpper(text)
+	}
+	return text
+}
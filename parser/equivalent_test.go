@@ -0,0 +1,55 @@
+package parser
+
+import "testing"
+
+func TestEquivalent(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		andOrder bool
+		want     bool
+	}{
+		// 空白、注释、分号、关键字大小写、反引号
+		{"SELECT a FROM t WHERE id = 1", "select  a\nfrom t /* c */ where id=1;", false, true},
+		{"SELECT `a` FROM `t`", "SELECT a FROM t", false, true},
+		{"SELECT a FROM t", "SELECT A FROM t", false, false},
+		{"SELECT a FROM t WHERE s = 'x'", "SELECT a FROM t WHERE s = 'X'", false, false},
+		// 表别名按出现顺序对应，AS 可以省略
+		{"SELECT x.a FROM t x WHERE x.id = 1", "SELECT y.a FROM t AS y WHERE y.id = 1", false, true},
+		{"SELECT x.a FROM t x JOIN u y ON x.id = y.id", "SELECT y.a FROM t y JOIN u x ON y.id = x.id", false, true},
+		{"SELECT x.a FROM t x JOIN u y ON x.id = y.id", "SELECT y.a FROM t x JOIN u y ON x.id = y.id", false, false},
+		// 列的顺序、列别名、多余的括号、数值写法
+		{"SELECT a, b FROM t", "SELECT b, a FROM t", false, false},
+		{"SELECT a AS x FROM t", "SELECT a AS y FROM t", false, false},
+		{"SELECT a FROM t WHERE (b = 1)", "SELECT a FROM t WHERE b = 1", false, false},
+		{"SELECT a FROM t WHERE b = 1", "SELECT a FROM t WHERE b = 1.0", false, false},
+		// AND 的顺序
+		{"SELECT a FROM t WHERE a = 1 AND b = 2", "SELECT a FROM t WHERE b = 2 AND a = 1", false, false},
+		{"SELECT a FROM t WHERE a = 1 AND b = 2 AND c = 3", "SELECT a FROM t WHERE c = 3 AND a = 1 AND b = 2", true, true},
+		{"SELECT a FROM t WHERE a = 1 AND b = 2", "SELECT a FROM t WHERE b = 2 && a = 1", true, true},
+		{"SELECT a FROM t WHERE a = 1 OR b = 2", "SELECT a FROM t WHERE b = 2 OR a = 1", true, false},
+		// a OR b AND c 是 a OR (b AND c)，AND 的两边可以交换，OR 的两边不能
+		{"SELECT a FROM t WHERE a = 1 OR b = 2 AND c = 3", "SELECT a FROM t WHERE a = 1 OR c = 3 AND b = 2", true, true},
+		{"SELECT a FROM t WHERE a = 1 AND b = 2 OR c = 3", "SELECT a FROM t WHERE b = 2 AND a = 1 OR c = 3", true, true},
+		{"SELECT a FROM t WHERE a = 1 OR b = 2 AND c = 3", "SELECT a FROM t WHERE c = 3 AND a = 1 OR b = 2", true, false},
+		{"SELECT a FROM t WHERE a = 1 OR b = 2 AND c = 3", "SELECT a FROM t WHERE (a = 1 OR b = 2) AND c = 3", true, false},
+		{"SELECT a FROM t WHERE a = 1 XOR b = 2 AND c = 3", "SELECT a FROM t WHERE a = 1 XOR c = 3 AND b = 2", true, true},
+		// 括号内部各自比较
+		{"SELECT a FROM t WHERE (a = 1 AND b = 2) OR c = 3", "SELECT a FROM t WHERE (b = 2 AND a = 1) OR c = 3", true, true},
+		{"SELECT a FROM t WHERE (a = 1 OR b = 2) AND c = 3", "SELECT a FROM t WHERE c = 3 AND (a = 1 OR b = 2)", true, true},
+		{"SELECT a FROM t WHERE x IN (SELECT y FROM u WHERE p = 1 AND q = 2)", "SELECT a FROM t WHERE x IN (SELECT y FROM u WHERE q = 2 AND p = 1)", true, true},
+	}
+	for _, c := range cases {
+		got, err := EquivalentWith(c.a, c.b, EquivalentOptions{AndOrder: c.andOrder})
+		if err != nil {
+			t.Errorf("EquivalentWith(%q, %q) error: %v", c.a, c.b, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("EquivalentWith(%q, %q, AndOrder=%v) = %v, want %v", c.a, c.b, c.andOrder, got, c.want)
+		}
+	}
+
+	if _, err := Equivalent("SELECT a FROM t", "SELECT FROM"); err == nil {
+		t.Errorf("Equivalent with a syntax error should fail")
+	}
+}
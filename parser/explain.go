@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// explainKinds ClickHouse EXPLAIN 的种类
+var explainKinds = map[string]bool{"AST": true, "SYNTAX": true, "PLAN": true, "PIPELINE": true, "ESTIMATE": true}
+
+// Explain 一条 EXPLAIN/DESCRIBE 语句
+type Explain struct {
+	Keyword        string   `json:"keyword"`        // EXPLAIN、DESCRIBE 或 DESC
+	Analyze        bool     `json:"analyze"`        // EXPLAIN ANALYZE
+	Format         string   `json:"format"`         // FORMAT=JSON 中的格式，大写
+	Kind           string   `json:"kind"`           // ClickHouse 的 EXPLAIN PLAN/PIPELINE/AST/SYNTAX/ESTIMATE
+	Options        []string `json:"options"`        // 其他选项原文，如 EXTENDED、PARTITIONS、header = 1
+	Statement      string   `json:"statement"`      // 被解释的语句原文，可继续交给 ParseTokens 等解析
+	StatementStart int      `json:"statementStart"` // Statement 在原始 SQL 中的字节偏移
+
+	// Result 被解释语句的解析结果，仅 ParseExplain 填写；被解释的不是 SELECT、INSERT 等语句
+	// （如 DESCRIBE t）或有语法错误时为 nil，错误见 Err，位置已换算到原始 SQL
+	Result *SqlParseResult `json:"-"`
+	Err    error           `json:"-"`
+
+	first int // 被解释语句第一个词法单元的类型
+}
+
+// explainable 被解释的是否为可以单独解析的语句
+func (e *Explain) explainable() bool {
+	switch e.first {
+	case MySqlLexerSELECT, MySqlLexerINSERT, MySqlLexerREPLACE, MySqlLexerUPDATE, MySqlLexerDELETE,
+		MySqlLexerWITH, MySqlLexerTABLE, MySqlLexerLR_BRACKET:
+		return true
+	}
+	return false
+}
+
+// ParseExplain 识别 EXPLAIN 的各种写法并拆出被解释的语句，sql 不是 EXPLAIN/DESCRIBE 时返回 false。
+//
+// 只做词法分析，不认识的选项按原文保留在 Options 中，不会导致失败；
+// 第一个既不是已知选项、也不是 name = value 形式的词法单元视为被解释语句的开始，
+// 被解释的语句再按默认选项交给 ParseSQL 解析，结果见 Explain.Result。
+func ParseExplain(sql string) (*Explain, bool) {
+	e, ok := parseExplain(sql)
+	if ok && e.explainable() {
+		e.Result, e.Err = ParseSQL(e.Statement)
+		e.Err = shiftError(e.Err, sql, e.StatementStart)
+	}
+	return e, ok
+}
+
+// parseExplain 同 ParseExplain，不解析被解释的语句
+func parseExplain(sql string) (*Explain, bool) {
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	lexer.RemoveErrorListeners()
+	offsets := byteOffsets(sql)
+
+	var tokens []antlr.Token
+	for t := lexer.NextToken(); t.GetTokenType() != antlr.TokenEOF; t = lexer.NextToken() {
+		if t.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		if len(tokens) == 0 {
+			switch t.GetTokenType() {
+			case MySqlLexerEXPLAIN, MySqlLexerDESCRIBE, MySqlLexerDESC:
+			default:
+				return nil, false
+			}
+		}
+		tokens = append(tokens, t)
+	}
+	if len(tokens) == 0 {
+		return nil, false
+	}
+	text := func(t antlr.Token) string { return sql[offsets[t.GetStart()]:offsets[t.GetStop()+1]] }
+	is := func(i, tokenType int) bool { return i < len(tokens) && tokens[i].GetTokenType() == tokenType }
+
+	e := &Explain{Keyword: strings.ToUpper(text(tokens[0])), StatementStart: len(sql)}
+
+	i := 1
+	for i < len(tokens) {
+		t := tokens[i]
+		upper := strings.ToUpper(text(t))
+		switch {
+		case t.GetTokenType() == MySqlLexerANALYZE:
+			e.Analyze = true
+			i++
+		case t.GetTokenType() == MySqlLexerFORMAT && is(i+1, MySqlLexerEQUAL_SYMBOL) && i+2 < len(tokens):
+			e.Format = strings.ToUpper(text(tokens[i+2]))
+			i += 3
+		case t.GetTokenType() == MySqlLexerEXTENDED || t.GetTokenType() == MySqlLexerPARTITIONS:
+			e.Options = append(e.Options, upper)
+			i++
+		case explainKinds[upper] && e.Kind == "":
+			e.Kind = upper
+			i++
+		case is(i+1, MySqlLexerEQUAL_SYMBOL) && i+2 < len(tokens):
+			// ClickHouse 的 header = 1 等设置
+			e.Options = append(e.Options, sql[offsets[t.GetStart()]:offsets[tokens[i+2].GetStop()+1]])
+			i += 3
+		case t.GetTokenType() == MySqlLexerCOMMA:
+			i++
+		default:
+			e.StatementStart = offsets[t.GetStart()]
+			e.first = t.GetTokenType()
+			e.Statement = strings.TrimRight(strings.TrimSpace(sql[e.StatementStart:]), ";")
+			return e, true
+		}
+	}
+	return e, true
+}
+
+// shiftError 把被解释语句中的错误位置换算为原始 SQL 中的位置，start 为语句在 sql 中的字节偏移
+func shiftError(err error, sql string, start int) error {
+	e, ok := err.(*ParseError)
+	if !ok {
+		return err
+	}
+	shifted := *e
+	prefix := sql[:start]
+	if shifted.Line == 1 {
+		shifted.Column += utf8.RuneCountInString(prefix[strings.LastIndex(prefix, "\n")+1:])
+	}
+	shifted.Line += strings.Count(prefix, "\n")
+	return &shifted
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseExplain(t *testing.T) {
+	cases := []struct {
+		sql       string
+		keyword   string
+		analyze   bool
+		format    string
+		kind      string
+		options   []string
+		statement string
+		tables    []string // 被解释语句引用的表，nil 表示不解析
+	}{
+		{sql: "EXPLAIN SELECT * FROM t", keyword: "EXPLAIN", statement: "SELECT * FROM t", tables: []string{"t"}},
+		{sql: "explain analyze select a from t1 join t2 on t1.id = t2.id;", keyword: "EXPLAIN", analyze: true, statement: "select a from t1 join t2 on t1.id = t2.id", tables: []string{"t1", "t2"}},
+		{sql: "EXPLAIN FORMAT=json SELECT 1 FROM t", keyword: "EXPLAIN", format: "JSON", statement: "SELECT 1 FROM t", tables: []string{"t"}},
+		{sql: "EXPLAIN EXTENDED DELETE FROM t WHERE id = 1", keyword: "EXPLAIN", options: []string{"EXTENDED"}, statement: "DELETE FROM t WHERE id = 1", tables: []string{"t"}},
+		{sql: "EXPLAIN PIPELINE header = 1 SELECT 1 FROM t", keyword: "EXPLAIN", kind: "PIPELINE", options: []string{"header = 1"}, statement: "SELECT 1 FROM t", tables: []string{"t"}},
+		{sql: "DESCRIBE t", keyword: "DESCRIBE", statement: "t"},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			e, ok := ParseExplain(c.sql)
+			if !ok {
+				t.Fatalf("not recognized as EXPLAIN")
+			}
+			if e.Keyword != c.keyword || e.Analyze != c.analyze || e.Format != c.format || e.Kind != c.kind {
+				t.Errorf("got %+v", e)
+			}
+			if !reflect.DeepEqual(e.Options, c.options) {
+				t.Errorf("Options = %q, want %q", e.Options, c.options)
+			}
+			if e.Statement != c.statement || c.sql[e.StatementStart:e.StatementStart+len(e.Statement)] != c.statement {
+				t.Errorf("Statement = %q at %d, want %q", e.Statement, e.StatementStart, c.statement)
+			}
+			if c.tables == nil {
+				if e.Result != nil || e.Err != nil {
+					t.Errorf("Result = %+v, Err = %v, want nil", e.Result, e.Err)
+				}
+				return
+			}
+			if e.Err != nil {
+				t.Fatalf("Err = %v", e.Err)
+			}
+			if !reflect.DeepEqual(e.Result.Tables, c.tables) {
+				t.Errorf("Result.Tables = %v, want %v", e.Result.Tables, c.tables)
+			}
+		})
+	}
+	if _, ok := ParseExplain("SELECT 1"); ok {
+		t.Errorf("SELECT recognized as EXPLAIN")
+	}
+}
+
+func TestParseSQLExplain(t *testing.T) {
+	cases := []struct {
+		sql     string
+		stmt    string
+		tables  []string
+		analyze bool
+		format  string
+	}{
+		{"EXPLAIN ANALYZE SELECT a FROM t WHERE b = 1", StatementExplain, []string{"t"}, true, ""},
+		{"EXPLAIN FORMAT=JSON SELECT a FROM t", StatementExplain, []string{"t"}, false, "JSON"},
+		{"EXPLAIN FORMAT=TREE UPDATE t SET a = 1", StatementExplain, []string{"t"}, false, "TREE"},
+		{"EXPLAIN SELECT a FROM t", StatementExplain, []string{"t"}, false, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result, err := ParseSQL(c.sql)
+			if err != nil {
+				t.Fatalf("ParseSQL: %v", err)
+			}
+			if result.StatementType != c.stmt {
+				t.Errorf("StatementType = %q, want %q", result.StatementType, c.stmt)
+			}
+			if !reflect.DeepEqual(result.Tables, c.tables) {
+				t.Errorf("Tables = %v, want %v", result.Tables, c.tables)
+			}
+			if result.Explain == nil || result.Explain.Analyze != c.analyze || result.Explain.Format != c.format {
+				t.Errorf("Explain = %+v", result.Explain)
+			}
+			if _, err := result.ToJSON(); err != nil {
+				t.Errorf("ToJSON: %v", err)
+			}
+		})
+	}
+
+	// 被解释语句中的错误位置换算到原始 SQL
+	_, err := ParseSQL("EXPLAIN ANALYZE\nSELECT a FROM WHERE")
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 || pe.Column != 14 {
+		t.Errorf("err = %v, want error at line 2:14", err)
+	}
+	_, err = ParseSQL("EXPLAIN ANALYZE SELECT a FROM WHERE")
+	if !errors.As(err, &pe) || pe.Line != 1 || pe.Column != 30 {
+		t.Errorf("err = %v, want error at line 1:30", err)
+	}
+
+	result, err := ParseSQL("DESCRIBE t")
+	if err != nil || result.StatementType != StatementOther || result.Explain != nil {
+		t.Errorf("DESCRIBE t: %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,106 @@
+package parser
+
+import "github.com/antlr/antlr4/runtime/Go/antlr"
+
+// InsertInfo INSERT 或 REPLACE 语句，两者由 StatementType 区分
+type InsertInfo struct {
+	Table       string   `json:"table"`       // 目标表，去掉反引号，默认小写（见 ParseOptions）
+	Columns     []string `json:"columns"`     // 列名列表，INSERT ... SET 时为 SET 中的列，没有时为空
+	Select      bool     `json:"select"`      // 是否为 INSERT ... SELECT，此时 Columns 等查询信息取自其中的 SELECT
+	OnDuplicate bool     `json:"onDuplicate"` // 是否有 ON DUPLICATE KEY UPDATE，REPLACE 始终为 false
+	// DuplicateColumns ON DUPLICATE KEY UPDATE 中更新的列，没有时为空
+	DuplicateColumns []string `json:"duplicateColumns"`
+
+	RowCount    int `json:"rowCount"`    // VALUES 中的行数，INSERT ... SET 为 1，INSERT ... SELECT 为 0
+	ColumnCount int `json:"columnCount"` // 列名列表的列数，没有列名列表时为 0
+	// MismatchRows 值的个数与列数不一致的行（下标从 0 开始）；没有列名列表时与第一行比较
+	MismatchRows []int `json:"mismatchRows"`
+}
+
+// insertStatement INSERT 与 REPLACE 语句共有的部分
+type insertStatement interface {
+	antlr.ParserRuleContext
+	TableName() ITableNameContext
+	GetColumns() IUidListContext
+	SET() antlr.TerminalNode
+	GetSetFirst() IUpdatedElementContext
+	GetSetElements() []IUpdatedElementContext
+	InsertStatementValue() IInsertStatementValueContext
+}
+
+func (l *sqlListener) EnterInsertStatement(ctx *InsertStatementContext) {
+	if l.result.Insert != nil {
+		return
+	}
+	insert := l.insertInfo(ctx)
+	insert.OnDuplicate = ctx.DUPLICATE() != nil
+	if insert.OnDuplicate {
+		for _, e := range append([]IUpdatedElementContext{ctx.GetDuplicatedFirst()}, ctx.GetDuplicatedElements()...) {
+			_, name := splitColumnName(e.(*UpdatedElementContext).FullColumnName().GetText())
+			insert.DuplicateColumns = append(insert.DuplicateColumns, name)
+		}
+	}
+	l.result.Insert = insert
+}
+
+// EnterReplaceStatement REPLACE 与 INSERT 的写法相同，同样填充 Insert
+func (l *sqlListener) EnterReplaceStatement(ctx *ReplaceStatementContext) {
+	if l.result.Insert != nil {
+		return
+	}
+	l.result.Insert = l.insertInfo(ctx)
+}
+
+func (l *sqlListener) insertInfo(ctx insertStatement) *InsertInfo {
+	insert := &InsertInfo{
+		Table:            l.fold(unquoteName(ctx.TableName().GetText())),
+		Columns:          []string{},
+		DuplicateColumns: []string{},
+		MismatchRows:     []int{},
+	}
+	if list, ok := ctx.GetColumns().(*UidListContext); ok {
+		for _, uid := range list.AllUid() {
+			insert.Columns = append(insert.Columns, uidText(uid))
+		}
+		insert.ColumnCount = len(insert.Columns)
+	}
+	if ctx.SET() != nil {
+		insert.RowCount = 1
+		for _, e := range append([]IUpdatedElementContext{ctx.GetSetFirst()}, ctx.GetSetElements()...) {
+			_, name := splitColumnName(e.(*UpdatedElementContext).FullColumnName().GetText())
+			insert.Columns = append(insert.Columns, name)
+		}
+	}
+	if value, ok := ctx.InsertStatementValue().(*InsertStatementValueContext); ok {
+		if value.SelectStatement() != nil {
+			insert.Select = true
+		} else {
+			insert.countRows(value)
+		}
+	}
+	return insert
+}
+
+// countRows 统计 VALUES 中的行数及每行值的个数，记录与列数不一致的行
+func (insert *InsertInfo) countRows(value *InsertStatementValueContext) {
+	want, count := insert.ColumnCount, 0
+	for _, child := range value.GetChildren() {
+		switch c := child.(type) {
+		case *ExpressionsWithDefaultsContext:
+			count = len(c.AllExpressionOrDefault())
+		case antlr.TerminalNode:
+			switch c.GetSymbol().GetTokenType() {
+			case MySqlParserLR_BRACKET:
+				count = 0
+			case MySqlParserRR_BRACKET:
+				if insert.ColumnCount == 0 && insert.RowCount == 0 {
+					want = count
+				}
+				if count != want {
+					insert.MismatchRows = append(insert.MismatchRows, insert.RowCount)
+				}
+				insert.RowCount++
+			}
+		}
+	}
+}
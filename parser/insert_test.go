@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	cases := []struct {
+		sql     string
+		tables  []string
+		table   string
+		columns []string
+		sel     bool
+		exprs   []string // 最外层查询的列，只有 INSERT ... SELECT 时非空
+	}{
+		{
+			sql:     "INSERT INTO t (a, b) VALUES (1, 2), (3, 4)",
+			tables:  []string{"t"},
+			table:   "t",
+			columns: []string{"a", "b"},
+			exprs:   []string{},
+		},
+		{
+			sql:     "INSERT IGNORE INTO `DB`.`Users` VALUES (1, 'x')",
+			tables:  []string{"db.users"},
+			table:   "db.users",
+			columns: []string{},
+			exprs:   []string{},
+		},
+		{
+			sql:     "INSERT INTO t SET a = 1, b = 2",
+			tables:  []string{"t"},
+			table:   "t",
+			columns: []string{"a", "b"},
+			exprs:   []string{},
+		},
+		{
+			sql:     "INSERT INTO t (`a`, `b`) VALUE (1, DEFAULT)",
+			tables:  []string{"t"},
+			table:   "t",
+			columns: []string{"a", "b"},
+			exprs:   []string{},
+		},
+		{
+			sql:     "INSERT INTO t (a) SELECT id FROM s WHERE x > 1",
+			tables:  []string{"t", "s"},
+			table:   "t",
+			columns: []string{"a"},
+			sel:     true,
+			exprs:   []string{"id"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			if result.StatementType != StatementInsert {
+				t.Errorf("StatementType = %q, want %q", result.StatementType, StatementInsert)
+			}
+			if !reflect.DeepEqual(result.Tables, c.tables) {
+				t.Errorf("Tables = %v, want %v", result.Tables, c.tables)
+			}
+			insert := result.Insert
+			if insert == nil {
+				t.Fatalf("Insert = nil")
+			}
+			if insert.Table != c.table {
+				t.Errorf("Table = %q, want %q", insert.Table, c.table)
+			}
+			if !reflect.DeepEqual(insert.Columns, c.columns) {
+				t.Errorf("Columns = %v, want %v", insert.Columns, c.columns)
+			}
+			if insert.Select != c.sel {
+				t.Errorf("Select = %v, want %v", insert.Select, c.sel)
+			}
+			exprs := []string{}
+			for _, col := range result.Columns {
+				exprs = append(exprs, col.Expr)
+			}
+			if !reflect.DeepEqual(exprs, c.exprs) {
+				t.Errorf("Columns = %v, want %v", exprs, c.exprs)
+			}
+		})
+	}
+
+	if result := mustParse(t, "SELECT * FROM t"); result.Insert != nil {
+		t.Errorf("SELECT: Insert = %+v, want nil", result.Insert)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	cases := []struct {
+		sql       string
+		stmt      string
+		table     string
+		columns   []string
+		duplicate []string
+	}{
+		{
+			sql:       "INSERT INTO t (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b), t.c = c + 1",
+			stmt:      StatementInsert,
+			table:     "t",
+			columns:   []string{"a", "b"},
+			duplicate: []string{"b", "c"},
+		},
+		{
+			sql:       "INSERT INTO db.t SET a = 1 ON DUPLICATE KEY UPDATE a = a + 1",
+			stmt:      StatementInsert,
+			table:     "db.t",
+			columns:   []string{"a"},
+			duplicate: []string{"a"},
+		},
+		{
+			sql:       "INSERT INTO t (a) VALUES (1)",
+			stmt:      StatementInsert,
+			table:     "t",
+			columns:   []string{"a"},
+			duplicate: []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result, err := ParseSQL(c.sql)
+			if err != nil {
+				t.Fatalf("ParseSQL: %v", err)
+			}
+			if result.StatementType != c.stmt {
+				t.Errorf("StatementType = %q, want %q", result.StatementType, c.stmt)
+			}
+			insert := result.Insert
+			if insert == nil {
+				t.Fatalf("Insert = nil")
+			}
+			if insert.Table != c.table {
+				t.Errorf("Table = %q, want %q", insert.Table, c.table)
+			}
+			if !reflect.DeepEqual(insert.Columns, c.columns) {
+				t.Errorf("Columns = %v, want %v", insert.Columns, c.columns)
+			}
+			if insert.OnDuplicate != (len(c.duplicate) > 0) {
+				t.Errorf("OnDuplicate = %v", insert.OnDuplicate)
+			}
+			if !reflect.DeepEqual(insert.DuplicateColumns, c.duplicate) {
+				t.Errorf("DuplicateColumns = %v, want %v", insert.DuplicateColumns, c.duplicate)
+			}
+		})
+	}
+}
+
+func TestInsertRowCount(t *testing.T) {
+	cases := []struct {
+		sql      string
+		rows     int
+		columns  int
+		mismatch []int
+	}{
+		{"INSERT INTO t (a, b) VALUES (1, 2), (3), (4, 5, 6)", 3, 2, []int{1, 2}},
+		{"INSERT INTO t VALUES (1, 2), (3, 4), (5)", 3, 0, []int{2}},
+		{"INSERT INTO t (a, b) VALUES (1, (SELECT 2)), (f(1, 2), DEFAULT)", 2, 2, []int{}},
+		{"INSERT INTO t (a) VALUES ()", 1, 1, []int{0}},
+		{"INSERT INTO t SET a = 1, b = 2", 1, 0, []int{}},
+		{"INSERT INTO t (a) SELECT 1", 0, 1, []int{}},
+	}
+	for _, c := range cases {
+		insert := mustParse(t, c.sql).Insert
+		if insert == nil {
+			t.Errorf("%q: Insert = nil", c.sql)
+			continue
+		}
+		if insert.RowCount != c.rows || insert.ColumnCount != c.columns || !reflect.DeepEqual(insert.MismatchRows, c.mismatch) {
+			t.Errorf("%q: RowCount, ColumnCount, MismatchRows = %d, %d, %v, want %d, %d, %v",
+				c.sql, insert.RowCount, insert.ColumnCount, insert.MismatchRows, c.rows, c.columns, c.mismatch)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJoins(t *testing.T) {
+	cases := []struct {
+		sql   string
+		joins []JoinInfo
+	}{
+		{
+			sql:   "SELECT * FROM a JOIN b ON a.id = b.id",
+			joins: []JoinInfo{{Type: JoinInner, Table: "b", Condition: "a.id = b.id"}},
+		},
+		{
+			sql:   "SELECT * FROM a LEFT JOIN b ON a.id = b.id RIGHT JOIN c USING (id, k)",
+			joins: []JoinInfo{{Type: JoinLeft, Table: "b", Condition: "a.id = b.id"}, {Type: JoinRight, Table: "c", Condition: "USING (id, k)", Using: []string{"id", "k"}}},
+		},
+		{
+			sql:   "SELECT * FROM a x LEFT OUTER JOIN `db`.`B` AS y ON x.id = y.id",
+			joins: []JoinInfo{{Type: JoinLeft, Table: "db.b", Alias: "y", Condition: "x.id = y.id"}},
+		},
+		{
+			sql:   "SELECT * FROM a CROSS JOIN b NATURAL LEFT JOIN c STRAIGHT_JOIN d ON d.k = a.k",
+			joins: []JoinInfo{{Type: JoinCross, Table: "b"}, {Type: JoinLeft, Natural: true, Table: "c"}, {Type: JoinStraight, Table: "d", Condition: "d.k = a.k"}},
+		},
+		{
+			sql:   "SELECT * FROM a JOIN (SELECT id FROM b JOIN c ON b.id = c.id) s ON s.id = a.id",
+			joins: []JoinInfo{{Type: JoinInner, Alias: "s", Condition: "s.id = a.id"}, {Type: JoinInner, Table: "c", Condition: "b.id = c.id"}},
+		},
+		{
+			sql:   "SELECT * FROM a, b",
+			joins: []JoinInfo{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			for i := range c.joins {
+				if c.joins[i].Using == nil {
+					c.joins[i].Using = []string{}
+				}
+			}
+			if !reflect.DeepEqual(result.Joins, c.joins) {
+				t.Errorf("Joins = %+v\nwant %+v", result.Joins, c.joins)
+			}
+		})
+	}
+}
+
+func TestLeftJoinWithoutAlias(t *testing.T) {
+	// a LEFT JOIN b 中的 LEFT 不是 a 的别名，a 仍可作为限定符
+	result := mustParse(t, "SELECT a.x FROM a LEFT JOIN b ON a.id = b.id")
+	if result.Columns[0].Table != "a" || result.Columns[0].TableAlias != "" {
+		t.Errorf("Columns[0] = %+v", result.Columns[0])
+	}
+	same, err := Equivalent("SELECT * FROM a LEFT JOIN b ON a.id = b.id", "select * from a left join b on a.id = b.id")
+	if err != nil || !same {
+		t.Errorf("Equivalent = %v, %v", same, err)
+	}
+}
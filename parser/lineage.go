@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// ColumnLineageInfo 最外层查询中一个输出列引用的源表
+type ColumnLineageInfo struct {
+	Column    string   `json:"column"`    // 输出列名：别名，没有别名时为列名或表达式原文，t.* 为原文
+	Tables    []string `json:"tables"`    // 引用的源表，派生表展开为其中的表，按出现顺序去重
+	Aggregate bool     `json:"aggregate"` // 包含聚合函数，同 ColumnInfo.IsAggregate
+	Ambiguous bool     `json:"ambiguous"` // 多表查询中含有不带表限定的列，Tables 为全部候选表
+}
+
+// ColumnLineage 返回最外层查询每个输出列到其引用的源表的映射，表别名按 FROM/JOIN 解析，
+// 详细信息见 ColumnLineages
+func ColumnLineage(sql string) (map[string][]string, error) {
+	lineages, err := ColumnLineages(sql)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string][]string, len(lineages))
+	for _, c := range lineages {
+		m[c.Column] = c.Tables
+	}
+	return m, nil
+}
+
+// ColumnLineages 按 SELECT 列表的顺序返回每个输出列引用的源表。
+//
+// 没有引用任何列的聚合（如 COUNT(*)）和 * 视为引用全部表；
+// 无法解析的表限定符（如外层查询的别名）原样作为表名。
+func ColumnLineages(sql string) ([]ColumnLineageInfo, error) {
+	l, err := parseSQL(sql, ParseOptions{})
+	if err != nil {
+		return nil, err
+	}
+	lineages := []ColumnLineageInfo{}
+	if len(l.result.Columns) > len(l.elements) {
+		lineages = append(lineages, ColumnLineageInfo{Column: "*", Tables: allTables(l.sources)})
+	}
+	for _, element := range l.elements {
+		lineages = append(lineages, columnLineage(element, l.sources, l.fold))
+	}
+	return lineages, nil
+}
+
+// tableSource FROM 中的一个表来源
+type tableSource struct {
+	name   string   // 别名，没有别名时为表名，经过 fold
+	table  string   // 表名，派生表为空，经过 fold
+	tables []string // 引用的表，派生表为其中引用的全部表
+}
+
+// collectSources 收集 FROM 中的表和派生表，不进入 ON 条件和派生表内部，表名和别名经过 fold
+func collectSources(node antlr.Tree, sources []tableSource, fold func(string) string) []tableSource {
+	switch n := node.(type) {
+	case *AtomTableItemContext:
+		table := fold(unquoteName(n.TableName().GetText()))
+		alias, _ := atomAlias(n)
+		name := fold(alias)
+		if name == "" {
+			name = table
+		}
+		return append(sources, tableSource{name: name, table: table, tables: []string{table}})
+	case *SubqueryTableItemContext:
+		return append(sources, tableSource{
+			name:   fold(uidText(n.GetAlias())),
+			tables: collectTables(n, nil, fold),
+		})
+	case IExpressionContext:
+		return sources
+	}
+	for _, child := range node.GetChildren() {
+		sources = collectSources(child, sources, fold)
+	}
+	return sources
+}
+
+// collectTables 收集子树中引用的全部表名，经过 fold 后去重
+func collectTables(node antlr.Tree, tables []string, fold func(string) string) []string {
+	if t, ok := node.(*TableNameContext); ok {
+		return appendUnique(tables, fold(unquoteName(t.GetText())))
+	}
+	for _, child := range node.GetChildren() {
+		tables = collectTables(child, tables, fold)
+	}
+	return tables
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, s := range list {
+			if s == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func allTables(sources []tableSource) []string {
+	tables := []string{}
+	for _, s := range sources {
+		tables = appendUnique(tables, s.tables...)
+	}
+	return tables
+}
+
+// resolveTable 按别名或表名查找表来源，db.t 形式的限定符同时按 t 查找；
+// 没有别名的 db.t 也可以用 t 限定，如 SELECT t.a FROM db.t。qualifier 经过 fold 后比较
+func resolveTable(qualifier string, sources []tableSource, fold func(string) string) (tableSource, bool) {
+	qualifier = fold(qualifier)
+	short := lastSegment(qualifier)
+	for _, s := range sources {
+		if s.name == qualifier || s.table == qualifier {
+			return s, true
+		}
+	}
+	for _, s := range sources {
+		if s.name == short || s.table == short {
+			return s, true
+		}
+	}
+	for _, s := range sources {
+		if s.name == s.table && lastSegment(s.table) == qualifier {
+			return s, true
+		}
+	}
+	return tableSource{}, false
+}
+
+// lastSegment 返回 db.t 形式名称的最后一段
+func lastSegment(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func columnLineage(element ISelectElementContext, sources []tableSource, fold func(string) string) ColumnLineageInfo {
+	col := selectColumn(element)
+	c := ColumnLineageInfo{Column: col.Alias, Tables: []string{}, Aggregate: col.IsAggregate}
+	if c.Column == "" {
+		c.Column = col.Name
+	}
+	if c.Column == "" {
+		c.Column = col.Expr
+	}
+
+	if star, ok := element.(*SelectStarElementContext); ok {
+		c.Column = col.Expr
+		if s, ok := resolveTable(unquoteName(star.FullId().GetText()), sources, fold); ok {
+			c.Tables = appendUnique(c.Tables, s.tables...)
+		} else {
+			c.Tables = appendUnique(c.Tables, fold(unquoteName(star.FullId().GetText())))
+		}
+		return c
+	}
+
+	refs := 0
+	var walk func(node antlr.Tree)
+	walk = func(node antlr.Tree) {
+		switch n := node.(type) {
+		case *FullColumnNameContext:
+			if isStringColumn(n) {
+				return
+			}
+			refs++
+			qualifier, _ := splitColumnName(n.GetText())
+			switch {
+			case qualifier == "" && len(sources) == 1:
+				c.Tables = appendUnique(c.Tables, sources[0].tables...)
+			case qualifier == "":
+				c.Ambiguous = true
+				c.Tables = appendUnique(c.Tables, allTables(sources)...)
+			default:
+				if s, ok := resolveTable(qualifier, sources, fold); ok {
+					c.Tables = appendUnique(c.Tables, s.tables...)
+				} else {
+					c.Tables = appendUnique(c.Tables, fold(qualifier))
+				}
+			}
+			return
+		}
+		// 标量子查询中的列属于子查询自己的作用域，只取其中的表
+		if subquerySelect(node) != nil {
+			refs++
+			c.Tables = collectTables(node, c.Tables, fold)
+			return
+		}
+		for _, child := range node.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(element)
+	if refs == 0 && c.Aggregate {
+		c.Tables = appendUnique(c.Tables, allTables(sources)...)
+	}
+	return c
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnLineages(t *testing.T) {
+	cases := []struct {
+		sql  string
+		want []ColumnLineageInfo
+	}{
+		{
+			sql: "SELECT a.id, b.name AS n FROM t1 a JOIN t2 b ON a.id = b.aid",
+			want: []ColumnLineageInfo{
+				{Column: "id", Tables: []string{"t1"}},
+				{Column: "n", Tables: []string{"t2"}},
+			},
+		},
+		{
+			sql: "SELECT o.id FROM orders o LEFT JOIN items i ON o.id = i.oid",
+			want: []ColumnLineageInfo{
+				{Column: "id", Tables: []string{"orders"}},
+			},
+		},
+		{
+			sql: "SELECT id FROM t1, t2",
+			want: []ColumnLineageInfo{
+				{Column: "id", Tables: []string{"t1", "t2"}, Ambiguous: true},
+			},
+		},
+		{
+			sql: "SELECT COUNT(*) AS c FROM t1 JOIN t2 ON t1.id = t2.id",
+			want: []ColumnLineageInfo{
+				{Column: "c", Tables: []string{"t1", "t2"}, Aggregate: true},
+			},
+		},
+		{
+			sql: "SELECT SUM(o.amt) + 1 total FROM db.orders o",
+			want: []ColumnLineageInfo{
+				{Column: "total", Tables: []string{"db.orders"}, Aggregate: true},
+			},
+		},
+		{
+			sql: "SELECT db.orders.id FROM db.orders",
+			want: []ColumnLineageInfo{
+				{Column: "id", Tables: []string{"db.orders"}},
+			},
+		},
+		{
+			sql: "SELECT x.v FROM (SELECT t1.v FROM t1 JOIN t2 ON t1.id = t2.id) x",
+			want: []ColumnLineageInfo{
+				{Column: "v", Tables: []string{"t1", "t2"}},
+			},
+		},
+		{
+			sql: "SELECT *, t1.* FROM t1 JOIN t2 ON 1 = 1",
+			want: []ColumnLineageInfo{
+				{Column: "*", Tables: []string{"t1", "t2"}},
+				{Column: "t1.*", Tables: []string{"t1"}},
+			},
+		},
+		{
+			sql: "SELECT 'abc' AS k, CONCAT('x', v) AS w FROM t1",
+			want: []ColumnLineageInfo{
+				{Column: "k", Tables: []string{}},
+				{Column: "w", Tables: []string{"t1"}},
+			},
+		},
+	}
+	for _, c := range cases {
+		got, err := ColumnLineages(c.sql)
+		if err != nil {
+			t.Errorf("ColumnLineages(%q): %v", c.sql, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ColumnLineages(%q) = %+v, want %+v", c.sql, got, c.want)
+		}
+	}
+}
+
+func TestColumnLineage(t *testing.T) {
+	got, err := ColumnLineage("SELECT a.id, b.name AS n FROM t1 a JOIN t2 b ON a.id = b.aid")
+	if err != nil {
+		t.Fatalf("ColumnLineage: %v", err)
+	}
+	want := map[string][]string{"id": {"t1"}, "n": {"t2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnLineage = %v, want %v", got, want)
+	}
+	if _, err := ColumnLineage("SELEC 1"); err == nil {
+		t.Error("ColumnLineage: want syntax error")
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+// logicalPrecedence 逻辑运算符按优先级从低到高排列
+var logicalPrecedence = []string{"OR", "XOR", "AND"}
+
+// logicalChain AND、XOR、OR 连接的一串条件。
+// 语法中三者优先级相同且左结合，a OR b AND c 会被解析为 (a OR b) AND c，
+// 因此先按出现顺序展开，再按 MySQL 的优先级（AND 高于 XOR 高于 OR）重新分组
+type logicalChain struct {
+	operands []IExpressionContext
+	ops      []string // ops[i] 连接 operands[i] 和 operands[i+1]
+}
+
+// newLogicalChain 展开逻辑表达式，括号和子查询内部不展开
+func newLogicalChain(expr IExpressionContext) logicalChain {
+	var c logicalChain
+	c.add(expr)
+	return c
+}
+
+func (c *logicalChain) add(expr IExpressionContext) {
+	logical, ok := expr.(*LogicalExpressionContext)
+	if !ok || logical.LogicalOperator() == nil {
+		c.operands = append(c.operands, expr)
+		return
+	}
+	operands := logical.AllExpression()
+	c.add(operands[0])
+	for _, operand := range operands[1:] {
+		c.ops = append(c.ops, logicalOperator(logical))
+		c.add(operand)
+	}
+}
+
+// split 按优先级最低的运算符拆分，返回该运算符和各组条件；只有一个条件时运算符为空
+func (c logicalChain) split() (string, []logicalChain) {
+	for _, op := range logicalPrecedence {
+		var groups []logicalChain
+		start := 0
+		for i, o := range c.ops {
+			if o == op {
+				groups = append(groups, logicalChain{operands: c.operands[start : i+1], ops: c.ops[start:i]})
+				start = i + 1
+			}
+		}
+		if groups != nil {
+			groups = append(groups, logicalChain{operands: c.operands[start:], ops: c.ops[start:]})
+			return op, groups
+		}
+	}
+	return "", []logicalChain{c}
+}
+
+// text 返回这串条件的原文
+func (c logicalChain) text() string {
+	return tokenText(c.operands[0].GetStart(), c.operands[len(c.operands)-1].GetStop())
+}
+
+// logicalOperator 返回规范化的运算符，&& 为 AND，|| 为 OR
+func logicalOperator(ctx *LogicalExpressionContext) string {
+	switch ctx.LogicalOperator().GetStart().GetTokenType() {
+	case MySqlParserAND, MySqlParserBIT_AND_OP:
+		return "AND"
+	case MySqlParserXOR:
+		return "XOR"
+	}
+	return "OR"
+}
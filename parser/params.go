@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// Param SQL 模板中的一个参数：位置参数 ?，或命名参数 :name、@name
+type Param struct {
+	Name   string // 命名参数的名称，不含 : 或 @ 前缀；位置参数为空
+	Index  int    // 位置参数的序号，从 0 开始；命名参数为 -1
+	Clause string // 所在子句，见 Clause* 常量；不在这些子句中（如 VALUES、SET）时为空
+	Depth  int    // 所在子句的查询嵌套深度，最外层为 0
+	Start  int    // 在原始 SQL 中的起始字节偏移
+	Stop   int    // 在原始 SQL 中的结束字节偏移（不含）
+	Line   int    // 行号，从 1 开始
+	Column int    // 列号（字符），从 0 开始
+}
+
+// NamedParam 一个命名参数的汇总
+type NamedParam struct {
+	Name    string   // 名称，区分大小写
+	Count   int      // 出现次数
+	Clauses []string // 出现的子句，按出现顺序去重，不在已知子句中的记为空
+}
+
+// ParamsInfo SQL 模板中的参数
+type ParamsInfo struct {
+	Params     []Param      // 全部参数，按出现顺序
+	Named      []NamedParam // 命名参数，按首次出现的顺序去重
+	Positional int          // 位置参数 ? 的个数
+	Duplicates []string     // 出现不止一次的命名参数名称
+}
+
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseParams 提取 sql 中的参数及其所在子句，sql 有语法错误时返回 error。
+//
+// :name 与 @name 视为同名参数；@name 在 MySQL 中是用户变量，这里同样按参数处理，@@ 开头的系统变量除外。
+// 语法分析时 ? 按数字、:name 按 @name 处理，所以参数可以出现在任何允许字面量或变量的位置
+func ParseParams(sql string) (*ParamsInfo, error) {
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	lexer.RemoveErrorListeners()
+	tokens := lexer.GetAllTokens()
+	offsets := byteOffsets(sql)
+
+	info := &ParamsInfo{Params: []Param{}, Named: []NamedParam{}, Duplicates: []string{}}
+	template := []byte(sql)
+	for i, t := range tokens {
+		start, stop := offsets[t.GetStart()], offsets[t.GetStop()+1]
+		p := Param{Index: -1, Start: start, Stop: stop, Line: t.GetLine(), Column: t.GetColumn()}
+		switch t.GetTokenType() {
+		case MySqlLexerERROR_RECONGNIGION:
+			if sql[start:stop] != "?" {
+				continue
+			}
+			template[start] = '0'
+			p.Index = info.Positional
+			info.Positional++
+		case MySqlLexerLOCAL_ID:
+			p.Name = strings.Trim(sql[start+1:stop], "`'\"")
+		case MySqlLexerCOLON_SYMB:
+			if i+1 >= len(tokens) || tokens[i+1].GetStart() != t.GetStop()+1 {
+				continue
+			}
+			next := tokens[i+1]
+			p.Stop = offsets[next.GetStop()+1]
+			p.Name = sql[stop:p.Stop]
+			if !paramName.MatchString(p.Name) {
+				continue
+			}
+			template[start] = '@'
+		default:
+			continue
+		}
+		info.Params = append(info.Params, p)
+	}
+
+	list, err := ParseTokens(string(template))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[int]int, len(list.Tokens))
+	for _, t := range list.Tokens {
+		index[t.Start] = t.Index
+	}
+	named := map[string]int{}
+	for i := range info.Params {
+		p := &info.Params[i]
+		if c, ok := innermostClause(list.Clauses, index[p.Start]); ok {
+			p.Clause, p.Depth = c.Name, c.Depth
+		}
+		if p.Name == "" {
+			continue
+		}
+		j, ok := named[p.Name]
+		if !ok {
+			j = len(info.Named)
+			named[p.Name] = j
+			info.Named = append(info.Named, NamedParam{Name: p.Name, Clauses: []string{}})
+		}
+		n := &info.Named[j]
+		n.Count++
+		n.Clauses = appendUnique(n.Clauses, p.Clause)
+		if n.Count == 2 {
+			info.Duplicates = append(info.Duplicates, p.Name)
+		}
+	}
+	return info, nil
+}
+
+// innermostClause 返回包含第 i 个词法单元的最内层子句
+func innermostClause(clauses []Clause, i int) (Clause, bool) {
+	found := false
+	var clause Clause
+	for _, c := range clauses {
+		if c.Start <= i && i <= c.Stop && (!found || c.Depth > clause.Depth) {
+			clause, found = c, true
+		}
+	}
+	return clause, found
+}
+
+// isParam 是否为占位符 ?。? 在词法分析时是隐藏通道中的错误词法单元，经过 paramSource 后为数字
+func isParam(t antlr.Token) bool {
+	switch t.GetTokenType() {
+	case MySqlLexerERROR_RECONGNIGION, MySqlLexerDECIMAL_LITERAL:
+		return t.GetText() == "?"
+	}
+	return false
+}
+
+// paramSource 按 ParseParams 的规则处理参数，使 SQL 模板能通过语法分析：
+// ? 转为默认通道的数字字面量，:name 转为变量 LOCAL_ID，文本均保持原样
+type paramSource struct {
+	antlr.Lexer
+	pending antlr.Token // 判断 :name 时多读的词法单元
+}
+
+func (s *paramSource) NextToken() antlr.Token {
+	t := s.next()
+	switch {
+	case isParam(t):
+		return s.create(t, t, MySqlLexerDECIMAL_LITERAL, "?")
+	case t.GetTokenType() == MySqlLexerCOLON_SYMB:
+		next := s.next()
+		if next.GetTokenType() != antlr.TokenEOF && next.GetStart() == t.GetStop()+1 && paramName.MatchString(next.GetText()) {
+			return s.create(t, next, MySqlLexerLOCAL_ID, ":"+next.GetText())
+		}
+		s.pending = next
+	}
+	return t
+}
+
+func (s *paramSource) next() antlr.Token {
+	if t := s.pending; t != nil {
+		s.pending = nil
+		return t
+	}
+	return s.Lexer.NextToken()
+}
+
+// create 创建从 start 到 stop 的默认通道词法单元
+func (s *paramSource) create(start, stop antlr.Token, ttype int, text string) antlr.Token {
+	return antlr.CommonTokenFactoryDEFAULT.Create(start.GetSource(), ttype, text, antlr.TokenDefaultChannel,
+		start.GetStart(), stop.GetStop(), start.GetLine(), start.GetColumn())
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	sql := "SELECT a FROM t WHERE a = :id AND b = ? OR c = @id AND d IN (SELECT x FROM u WHERE y = ?) ORDER BY :sort LIMIT ?, :n"
+	info, err := ParseParams(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type param struct {
+		Name   string
+		Index  int
+		Clause string
+		Depth  int
+		Text   string
+	}
+	var got []param
+	for _, p := range info.Params {
+		got = append(got, param{p.Name, p.Index, p.Clause, p.Depth, sql[p.Start:p.Stop]})
+	}
+	want := []param{
+		{"id", -1, ClauseWhere, 0, ":id"},
+		{"", 0, ClauseWhere, 0, "?"},
+		{"id", -1, ClauseWhere, 0, "@id"},
+		{"", 1, ClauseWhere, 1, "?"},
+		{"sort", -1, ClauseOrderBy, 0, ":sort"},
+		{"", 2, ClauseLimit, 0, "?"},
+		{"n", -1, ClauseLimit, 0, ":n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Params = %+v\nwant %+v", got, want)
+	}
+	if info.Positional != 3 || !reflect.DeepEqual(info.Duplicates, []string{"id"}) {
+		t.Errorf("Positional, Duplicates = %d, %v", info.Positional, info.Duplicates)
+	}
+	if n := info.Named[0]; n.Name != "id" || n.Count != 2 || !reflect.DeepEqual(n.Clauses, []string{ClauseWhere}) {
+		t.Errorf("Named[0] = %+v", n)
+	}
+
+	// ParseSQL 接受同样的模板，LIMIT 中占位符的序号与 ParseParams 一致
+	result := mustParse(t, sql)
+	if want := (&LimitInfo{Offset: -1, Count: -1, OffsetParam: 2, CountParam: -1}); !reflect.DeepEqual(result.Limit, want) {
+		t.Errorf("Limit = %+v, want %+v", result.Limit, want)
+	}
+	if !reflect.DeepEqual(result.Tables, []string{"t", "u"}) {
+		t.Errorf("Tables = %v", result.Tables)
+	}
+
+	// 字符串中的 ? 和 :name 不是参数
+	quoted := "SELECT a FROM t WHERE b = '?' AND c = ':x' AND d = ?"
+	info, err = ParseParams(quoted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Params) != 1 || info.Params[0].Index != 0 || quoted[info.Params[0].Start:] != "?" {
+		t.Errorf("Params = %+v", info.Params)
+	}
+	if result := mustParse(t, quoted); len(result.Where) != 3 {
+		t.Errorf("Where = %q", result.Where)
+	}
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// PredicateInfo WHERE 中按顶层 AND 拆分出的一个条件。
+// 各个标记只是语法上的提示，是否告警由调用方决定
+type PredicateInfo struct {
+	Text string `json:"text"` // 条件原文
+	// Operator 大写的运算符，如 =、<>、IN、NOT IN、BETWEEN、LIKE、IS NOT NULL、= ANY；
+	// OR/XOR 连接的条件为 OR/XOR（|| 也为 OR），NOT (...) 为 NOT；括号或其他无法归类的表达式为空
+	Operator string `json:"operator"`
+	// Operands 操作数原文，第一个是左侧，之后依次为右侧各项：
+	// BETWEEN 为下限和上限，IN 列表为各个值，IN 子查询和 = ANY 为子查询，OR 为各个分支
+	Operands        []string `json:"operands"`
+	FuncOnColumn    bool     `json:"funcOnColumn"`    // 比较的一侧是包含列的函数调用，如 YEAR(created) = 2024，通常无法使用索引
+	LeadingWildcard bool     `json:"leadingWildcard"` // LIKE 的模式以 % 或 _ 开头，如 col LIKE '%x'，无法使用索引前缀
+}
+
+// predicateInfo 分析一个条件
+func predicateInfo(expr IExpressionContext) PredicateInfo {
+	info := PredicateInfo{Text: originalText(expr), Operands: []string{}}
+	switch e := expr.(type) {
+	case *LogicalExpressionContext:
+		// 按优先级取最外层的运算符，a OR b AND c 的操作数为 a 和 b AND c
+		op, groups := newLogicalChain(e).split()
+		info.Operator = op
+		for _, group := range groups {
+			info.Operands = append(info.Operands, group.text())
+		}
+		return info
+	case *NotExpressionContext:
+		info.Operator = "NOT"
+		info.Operands = append(info.Operands, originalText(e.Expression()))
+		return info
+	case *PredicateExpressionContext:
+	default:
+		return info
+	}
+
+	var operands []IPredicateContext
+	switch p := expr.(*PredicateExpressionContext).Predicate().(type) {
+	case *BinaryComparisonPredicateContext:
+		info.Operator = strings.Join(strings.Fields(originalText(p.ComparisonOperator())), "")
+		operands = []IPredicateContext{p.GetLeft(), p.GetRight()}
+		info.Operands = append(info.Operands, originalText(p.GetLeft()), originalText(p.GetRight()))
+	case *SubqueryComparisonPredicateContext:
+		info.Operator = strings.Join(strings.Fields(originalText(p.ComparisonOperator())), "") + " " + strings.ToUpper(p.GetQuantifier().GetText())
+		operands = []IPredicateContext{p.Predicate()}
+		info.Operands = append(info.Operands, originalText(p.Predicate()), originalText(p.SelectStatement()))
+	case *InPredicateContext:
+		info.Operator = notOperator(p.NOT(), "IN")
+		operands = []IPredicateContext{p.Predicate()}
+		info.Operands = append(info.Operands, originalText(p.Predicate()))
+		if p.SelectStatement() != nil {
+			info.Operands = append(info.Operands, originalText(p.SelectStatement()))
+		} else if list, ok := p.Expressions().(*ExpressionsContext); ok {
+			for _, value := range list.AllExpression() {
+				info.Operands = append(info.Operands, originalText(value))
+			}
+		}
+	case *BetweenPredicateContext:
+		info.Operator = notOperator(p.NOT(), "BETWEEN")
+		operands = []IPredicateContext{p.Predicate(0)}
+		for _, operand := range p.AllPredicate() {
+			info.Operands = append(info.Operands, originalText(operand))
+		}
+	case *IsNullPredicateContext:
+		info.Operator = "IS " + strings.ToUpper(strings.Join(strings.Fields(originalText(p.NullNotnull())), " "))
+		operands = []IPredicateContext{p.Predicate()}
+		info.Operands = append(info.Operands, originalText(p.Predicate()))
+	case *LikePredicateContext:
+		info.Operator = notOperator(p.NOT(), "LIKE")
+		operands = []IPredicateContext{p.Predicate(0)}
+		for _, operand := range p.AllPredicate() {
+			info.Operands = append(info.Operands, originalText(operand))
+		}
+		if pattern := p.Predicate(1); pattern != nil {
+			text := strings.TrimLeft(originalText(pattern), "'\"")
+			info.LeadingWildcard = strings.HasPrefix(text, "%") || strings.HasPrefix(text, "_")
+		}
+	case *RegexpPredicateContext:
+		info.Operator = notOperator(p.NOT(), strings.ToUpper(p.GetRegex().GetText()))
+		operands = []IPredicateContext{p.Predicate(0)}
+		for _, operand := range p.AllPredicate() {
+			info.Operands = append(info.Operands, originalText(operand))
+		}
+	}
+	for _, operand := range operands {
+		if isFuncOnColumn(operand) {
+			info.FuncOnColumn = true
+		}
+	}
+	return info
+}
+
+func notOperator(not antlr.TerminalNode, op string) string {
+	if not != nil {
+		return "NOT " + op
+	}
+	return op
+}
+
+// isFuncOnColumn 判断操作数是否为参数中引用了列的函数调用
+func isFuncOnColumn(operand IPredicateContext) bool {
+	atom, ok := operand.(*ExpressionAtomPredicateContext)
+	if !ok {
+		return false
+	}
+	call, ok := atom.ExpressionAtom().(*FunctionCallExpressionAtomContext)
+	return ok && containsColumn(call)
+}
+
+// containsColumn 判断语法树中是否引用了列，不进入子查询
+func containsColumn(tree antlr.Tree) bool {
+	switch tree.(type) {
+	case *FullColumnNameContext:
+		return true
+	case *SelectStatementContext:
+		return false
+	}
+	for i := 0; i < tree.GetChildCount(); i++ {
+		if containsColumn(tree.GetChild(i)) {
+			return true
+		}
+	}
+	return false
+}
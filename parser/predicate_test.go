@@ -0,0 +1,87 @@
+package parser
+
+import "testing"
+
+func TestPredicateIndexHints(t *testing.T) {
+	cases := []struct {
+		cond            string
+		funcOnColumn    bool
+		leadingWildcard bool
+	}{
+		{"YEAR(created) = 2024", true, false},
+		{"created >= '2024-01-01'", false, false},
+		{"LOWER(name) IN ('a', 'b')", true, false},
+		{"DATE(created) BETWEEN '2024-01-01' AND '2024-02-01'", true, false},
+		{"NOW() > created", false, false},
+		{"name LIKE '%abc'", false, true},
+		{"name LIKE '_bc'", false, true},
+		{"name LIKE 'abc%'", false, false},
+		{"UPPER(name) NOT LIKE '%X'", true, true},
+		{"IFNULL(a, 0) IS NULL", true, false},
+	}
+	for _, c := range cases {
+		t.Run(c.cond, func(t *testing.T) {
+			result := mustParse(t, "SELECT * FROM t WHERE "+c.cond)
+			if len(result.Predicates) != 1 {
+				t.Fatalf("got %d predicates", len(result.Predicates))
+			}
+			p := result.Predicates[0]
+			if p.FuncOnColumn != c.funcOnColumn {
+				t.Errorf("FuncOnColumn = %v, want %v", p.FuncOnColumn, c.funcOnColumn)
+			}
+			if p.LeadingWildcard != c.leadingWildcard {
+				t.Errorf("LeadingWildcard = %v, want %v", p.LeadingWildcard, c.leadingWildcard)
+			}
+		})
+	}
+}
+
+func TestPredicateBreakdown(t *testing.T) {
+	cases := []struct {
+		cond     string
+		operator string
+		operands []string
+	}{
+		{"a = 1", "=", []string{"a", "1"}},
+		{"a < > 1", "<>", []string{"a", "1"}},
+		{"t.a != b", "!=", []string{"t.a", "b"}},
+		{"a IN (1, 2, 3)", "IN", []string{"a", "1", "2", "3"}},
+		{"a not in (select id from s)", "NOT IN", []string{"a", "select id from s"}},
+		{"a BETWEEN 1 AND 10", "BETWEEN", []string{"a", "1", "10"}},
+		{"a is not null", "IS NOT NULL", []string{"a"}},
+		{"name LIKE 'x%'", "LIKE", []string{"name", "'x%'"}},
+		{"name NOT REGEXP '^a'", "NOT REGEXP", []string{"name", "'^a'"}},
+		{"a > ALL (SELECT id FROM s)", "> ALL", []string{"a", "SELECT id FROM s"}},
+		{"a = 1 OR b = 2", "OR", []string{"a = 1", "b = 2"}},
+		{"a = 1 || b = 2 OR c = 3", "OR", []string{"a = 1", "b = 2", "c = 3"}},
+		{"a = 1 OR b = 2 AND c = 3", "OR", []string{"a = 1", "b = 2 AND c = 3"}},
+		{"a = 1 AND b = 2 OR c = 3", "OR", []string{"a = 1 AND b = 2", "c = 3"}},
+		{"a = 1 XOR b = 2 AND c = 3 OR d = 4", "OR", []string{"a = 1 XOR b = 2 AND c = 3", "d = 4"}},
+		{"a = 1 AND b = 2 XOR c = 3", "XOR", []string{"a = 1 AND b = 2", "c = 3"}},
+		{"NOT (a = 1)", "NOT", []string{"(a = 1)"}},
+		{"(a = 1)", "", []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.cond, func(t *testing.T) {
+			result := mustParse(t, "SELECT * FROM t WHERE "+c.cond)
+			if len(result.Predicates) != 1 {
+				t.Fatalf("got %d predicates", len(result.Predicates))
+			}
+			p := result.Predicates[0]
+			if p.Text != c.cond {
+				t.Errorf("Text = %q, want %q", p.Text, c.cond)
+			}
+			if p.Operator != c.operator {
+				t.Errorf("Operator = %q, want %q", p.Operator, c.operator)
+			}
+			if len(p.Operands) != len(c.operands) {
+				t.Fatalf("Operands = %q, want %q", p.Operands, c.operands)
+			}
+			for i := range p.Operands {
+				if p.Operands[i] != c.operands[i] {
+					t.Errorf("Operands = %q, want %q", p.Operands, c.operands)
+				}
+			}
+		})
+	}
+}
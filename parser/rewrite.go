@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// RewriteTables 按 mapping 替换 sql 中引用的表名，其余内容（列名、别名、空白、注释）原样保留。
+//
+// mapping 的键不区分大小写、不带反引号，可以是 db.tbl 或 tbl：
+// db.tbl 先按完整名称查找，找到时整体替换；否则按 tbl 查找，只替换表名部分并保留 db 限定。
+// 原表名带反引号时，新表名的各段同样加上反引号。
+// 被替换的表没有别名时，以表名作限定符的列（orders.id）和星号（orders.*）一并替换，
+// 限定符与语句中的别名同名时视为别名，不替换。
+func RewriteTables(sql string, mapping map[string]string) (string, error) {
+	list, tree, err := parseTokens(sql)
+	if err != nil {
+		return "", err
+	}
+	names := make(map[string]string, len(mapping))
+	for k, v := range mapping {
+		names[strings.ToLower(k)] = v
+	}
+
+	r := NewRewriter(list)
+	// qualifiers 记录被替换且没有别名的表：限定符（不带反引号、小写）到新名称（不带反引号）
+	qualifiers := map[string]string{}
+	aliases := map[string]bool{}
+	var refs []replaceOp // 列和星号的表限定符所在的词法单元范围
+	var walk func(node antlr.Tree) error
+	walk = func(node antlr.Tree) error {
+		switch n := node.(type) {
+		case *TableNameContext:
+			start, stop := n.GetStart().GetTokenIndex(), n.GetStop().GetTokenIndex()
+			orig := list.SQL[list.Tokens[start].Start:list.Tokens[stop].Stop]
+			name, ok := rewriteTableName(orig, names)
+			if !ok {
+				return nil
+			}
+			if !hasAlias(n) {
+				newName := unquoteName(name)
+				qualifiers[strings.ToLower(unquoteName(orig))] = newName
+				if prefix, last := splitQualifier(orig); prefix != "" {
+					_, newLast := splitQualifier(newName)
+					qualifiers[strings.ToLower(unquoteName(last))] = newLast
+				}
+			}
+			return r.Replace(start, stop, name)
+		case *AtomTableItemContext:
+			if alias, _ := atomAlias(n); alias != "" {
+				aliases[strings.ToLower(alias)] = true
+			}
+		case *SubqueryTableItemContext:
+			aliases[strings.ToLower(uidText(n.GetAlias()))] = true
+		case *SingleUpdateStatementContext:
+			if n.Uid() != nil {
+				aliases[strings.ToLower(uidText(n.Uid()))] = true
+			}
+		case *CteNameContext:
+			aliases[strings.ToLower(uidText(n.Uid()))] = true
+		case *FullColumnNameContext:
+			if from, to, ok := columnQualifier(n); ok {
+				refs = append(refs, replaceOp{from: from, to: to})
+			}
+			return nil
+		case *SelectStarElementContext:
+			id := n.FullId()
+			refs = append(refs, replaceOp{from: id.GetStart().GetTokenIndex(), to: id.GetStop().GetTokenIndex()})
+			return nil
+		}
+		for _, child := range node.GetChildren() {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(tree); err != nil {
+		return "", err
+	}
+	// 限定符可能出现在 FROM 之前，表名全部处理完后再替换
+	for _, ref := range refs {
+		orig := list.SQL[list.Tokens[ref.from].Start:list.Tokens[ref.to].Stop]
+		key := strings.ToLower(unquoteName(orig))
+		name, ok := qualifiers[key]
+		if !ok || aliases[key] {
+			continue
+		}
+		if err := r.Replace(ref.from, ref.to, quoteLike(name, orig)); err != nil {
+			return "", err
+		}
+	}
+	return r.String(), nil
+}
+
+// rewriteTableName 返回替换后的表名原文，mapping 中没有时返回 false
+func rewriteTableName(text string, mapping map[string]string) (string, bool) {
+	if name, ok := mapping[strings.ToLower(unquoteName(text))]; ok {
+		return quoteLike(name, text), true
+	}
+	prefix, last := splitQualifier(text)
+	if prefix == "" {
+		return "", false
+	}
+	if name, ok := mapping[strings.ToLower(unquoteName(last))]; ok {
+		return prefix + quoteLike(name, last), true
+	}
+	return "", false
+}
+
+// hasAlias 表名所在的表来源是否带有别名
+func hasAlias(ctx *TableNameContext) bool {
+	switch p := ctx.GetParent().(type) {
+	case *AtomTableItemContext:
+		alias, _ := atomAlias(p)
+		return alias != ""
+	case *SingleUpdateStatementContext:
+		return p.Uid() != nil
+	}
+	return false
+}
+
+// columnQualifier 返回 t.col、db.t.col 中表限定符所在的词法单元范围，没有限定符时返回 false
+func columnQualifier(ctx *FullColumnNameContext) (int, int, bool) {
+	dotted := ctx.AllDottedId()
+	if ctx.Uid() == nil || len(dotted) == 0 {
+		return 0, 0, false
+	}
+	stop := ctx.Uid().GetStop()
+	if len(dotted) > 1 {
+		stop = dotted[len(dotted)-2].GetStop()
+	}
+	return ctx.GetStart().GetTokenIndex(), stop.GetTokenIndex(), true
+}
+
+// splitQualifier 按反引号外的最后一个点拆分 db.tbl，返回 "db." 和 "tbl"
+func splitQualifier(text string) (string, string) {
+	quoted := false
+	for i := len(text) - 1; i >= 0; i-- {
+		switch text[i] {
+		case '`':
+			quoted = !quoted
+		case '.':
+			if !quoted {
+				return text[:i+1], text[i+1:]
+			}
+		}
+	}
+	return "", text
+}
+
+// quoteLike 原名称带反引号时给 name 的各段加上反引号
+func quoteLike(name, orig string) string {
+	if !strings.HasPrefix(orig, "`") {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.ReplaceAll(part, "`", "``") + "`"
+	}
+	return strings.Join(parts, ".")
+}
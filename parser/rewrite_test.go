@@ -0,0 +1,62 @@
+package parser
+
+import "testing"
+
+func TestRewriteTables(t *testing.T) {
+	mapping := map[string]string{"orders": "orders_v2", "DB.Users": "db2.users_1"}
+	cases := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT o.id /* keep */ FROM orders o WHERE o.id = 1",
+			want: "SELECT o.id /* keep */ FROM orders_v2 o WHERE o.id = 1",
+		},
+		{
+			sql:  "SELECT orders.id, orders.* FROM orders",
+			want: "SELECT orders_v2.id, orders_v2.* FROM orders_v2",
+		},
+		{
+			sql:  "SELECT `orders`.id FROM `orders` WHERE orders.x = 1",
+			want: "SELECT `orders_v2`.id FROM `orders_v2` WHERE orders_v2.x = 1",
+		},
+		{
+			sql:  "SELECT db.orders.id, orders.id FROM db.orders",
+			want: "SELECT db.orders_v2.id, orders_v2.id FROM db.orders_v2",
+		},
+		{
+			sql:  "SELECT db.users.id, users.name FROM db.users JOIN orders ON orders.uid = users.id",
+			want: "SELECT db2.users_1.id, users_1.name FROM db2.users_1 JOIN orders_v2 ON orders_v2.uid = users_1.id",
+		},
+		{
+			sql:  "UPDATE orders SET orders.x = 1 WHERE orders.id = 2",
+			want: "UPDATE orders_v2 SET orders_v2.x = 1 WHERE orders_v2.id = 2",
+		},
+		{
+			sql:  "DELETE FROM orders WHERE orders.id IN (SELECT orders.id FROM orders)",
+			want: "DELETE FROM orders_v2 WHERE orders_v2.id IN (SELECT orders_v2.id FROM orders_v2)",
+		},
+		{
+			// orders 是 t 的别名
+			sql:  "SELECT orders.id FROM t orders",
+			want: "SELECT orders.id FROM t orders",
+		},
+		{
+			sql:  "SELECT users.id FROM users",
+			want: "SELECT users.id FROM users",
+		},
+	}
+	for _, c := range cases {
+		got, err := RewriteTables(c.sql, mapping)
+		if err != nil {
+			t.Errorf("RewriteTables(%q): %v", c.sql, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("RewriteTables(%q) = %q, want %q", c.sql, got, c.want)
+		}
+	}
+	if _, err := RewriteTables("SELEC 1", mapping); err == nil {
+		t.Error("RewriteTables: want syntax error")
+	}
+}
@@ -0,0 +1,616 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// SqlParseResult 一条 SQL 的解析结果。
+// Columns、Where、GroupBy、OrderBy、Limit 取自最外层查询，UNION 时 Columns、Where 取第一个查询；
+// UPDATE、DELETE 语句的 Where、OrderBy、Limit 取自语句本身；
+// Tables 包含子查询中引用的表
+type SqlParseResult struct {
+	StatementType string `json:"statementType"` // 第一条语句的类型，见 Statement* 常量；空 SQL 时为空
+
+	Tables  []string     `json:"tables"`  // 引用的表，去掉反引号，默认小写（见 ParseOptions），按首次出现的顺序去重
+	Columns []ColumnInfo `json:"columns"` // SELECT 列表
+	GroupBy []string     `json:"groupBy"` // GROUP BY 表达式原文
+
+	GroupByItems []GroupByInfo `json:"groupByItems"` // 与 GroupBy 一一对应，带有解析后的表名
+
+	OrderBy []OrderByInfo `json:"orderBy"`
+	Limit   *LimitInfo    `json:"limit"` // 没有 LIMIT 时为 nil
+	Joins   []JoinInfo    `json:"joins"` // 按出现顺序，包含子查询中的 JOIN
+	Where   []string      `json:"where"` // 最外层 WHERE 按顶层 AND 拆分后的各个条件原文
+
+	WhereExpr string `json:"whereExpr"` // 最外层 WHERE 的完整条件原文
+
+	Predicates []PredicateInfo `json:"predicates"` // 与 Where 一一对应的条件分析
+
+	SubQueries []SubQueryInfo `json:"subQueries"` // 所有子查询，按出现顺序，嵌套的子查询各占一项
+	With       []WithInfo     `json:"with"`       // WITH 中的 CTE，CTE 名称不计入 Tables
+	Unions     []UnionInfo    `json:"unions"`     // UNION 的各个分支（不含第一个查询），包含子查询中的 UNION
+
+	UnionOrderBy string `json:"unionOrderBy"` // 作用于最外层整个 UNION 的 ORDER BY 原文，解析结果见 OrderBy；没有时为空
+	UnionLimit   string `json:"unionLimit"`   // 作用于最外层整个 UNION 的 LIMIT 原文，解析结果见 Limit；没有时为空
+
+	Insert *InsertInfo `json:"insert"` // INSERT、REPLACE 语句，其他语句为 nil
+	Update *UpdateInfo `json:"update"` // UPDATE 语句，其他语句为 nil
+	Delete *DeleteInfo `json:"delete"` // DELETE 语句，其他语句为 nil
+
+	CreateTable *CreateTableInfo `json:"createTable"` // CREATE TABLE 语句，其他语句为 nil
+	Alter       *AlterTable      `json:"alter"`       // ALTER TABLE 语句，其他语句为 nil
+	Explain     *Explain         `json:"explain"`     // EXPLAIN 的选项，其他语句为 nil，见 StatementExplain
+
+	Errors     []*ParseError `json:"errors"`     // ParseOptions.Lenient 时的全部语法错误，按出现顺序
+	Directives []Directive   `json:"directives"` // 注释中的忽略指令，见 ParseDirectives
+}
+
+// ColumnInfo SELECT 列表中的一项
+type ColumnInfo struct {
+	Expr       string `json:"expr"`       // 原文（不含别名），如 inv.qty、SUM(qty)
+	Table      string `json:"table"`      // 列所属的表，别名已解析为表名，派生表和 CTE 见 TableDerived；无法确定时为空
+	TableAlias string `json:"tableAlias"` // 列名的限定符是别名（或派生表、CTE 的名称）时为该限定符
+	Name       string `json:"name"`       // 列名，* 表示全部列；函数和表达式为空
+	Alias      string `json:"alias"`      // AS 指定的别名
+
+	IsFunction   bool   `json:"isFunction"`   // 整个表达式是一个函数调用，如 COUNT(*)、SUM(ROUND(x, 2))；a + SUM(x) 不算
+	FunctionName string `json:"functionName"` // IsFunction 时最外层函数的名称，大写，如 SUM(ROUND(x, 2)) 为 SUM
+
+	IsAggregate bool   `json:"isAggregate"` // 包含聚合函数（带 OVER 的窗口函数不算），如 COUNT(*)、ROUND(AVG(x), 2)
+	Function    string `json:"function"`    // 第一个聚合函数的名称，大写，如 COUNT
+	Distinct    bool   `json:"distinct"`    // 该聚合函数带 DISTINCT，如 COUNT(DISTINCT id)
+}
+
+// JOIN 类型。MySQL 不支持 FULL OUTER JOIN，a FULL JOIN b 会按 MySQL 的规则解析为别名为 full 的 a 与 b 做 INNER JOIN
+const (
+	JoinInner    = "INNER" // JOIN、INNER JOIN
+	JoinCross    = "CROSS"
+	JoinLeft     = "LEFT"
+	JoinRight    = "RIGHT"
+	JoinStraight = "STRAIGHT_JOIN"
+)
+
+// JoinInfo 一个 JOIN
+type JoinInfo struct {
+	Type      string   `json:"type"`      // 见 Join* 常量，NATURAL LEFT JOIN 为 LEFT 且 Natural 为 true
+	Natural   bool     `json:"natural"`   // 是否为 NATURAL JOIN
+	Table     string   `json:"table"`     // 被连接的表，子查询时为空
+	Alias     string   `json:"alias"`     // 表或子查询的别名
+	Condition string   `json:"condition"` // ON 条件原文；USING 时为 USING (a, b)；没有条件时为空
+	Using     []string `json:"using"`     // USING 中的列
+}
+
+// OrderByInfo ORDER BY 中的一项
+type OrderByInfo struct {
+	Expr string `json:"expr"`
+	Desc bool   `json:"desc"`
+
+	Column     string `json:"column"`     // 表达式只是一个列名时为该列名，否则为空；SELECT 中的别名也在这里
+	Table      string `json:"table"`      // 列所属的表，同 ColumnInfo.Table
+	TableAlias string `json:"tableAlias"` // 同 ColumnInfo.TableAlias
+}
+
+// GroupByInfo GROUP BY 中的一项，字段含义同 OrderByInfo
+type GroupByInfo struct {
+	Expr       string `json:"expr"`
+	Column     string `json:"column"`
+	Table      string `json:"table"`
+	TableAlias string `json:"tableAlias"`
+}
+
+// LimitInfo LIMIT 子句，支持 LIMIT n、LIMIT offset, n、LIMIT n OFFSET offset，
+// 值不是数字字面量（如占位符 ? 或变量）时为 -1
+type LimitInfo struct {
+	Offset int64 `json:"offset"`
+	Count  int64 `json:"count"`
+
+	// 值为占位符 ? 时该占位符的序号，从 0 开始，与 ParseParams 返回的 Param.Index 一致；否则为 -1
+	OffsetParam int `json:"offsetParam"`
+	CountParam  int `json:"countParam"`
+}
+
+// ParseOptions ParseSQL 的选项
+type ParseOptions struct {
+	// PreserveCase 为 true 时表名、表别名和 CTE 名称保留原始大小写，并区分大小写地匹配别名，
+	// 适用于区分表名大小写的 MySQL（lower_case_table_names=0，如 Linux 上的默认配置）。
+	// 默认转为小写；列名和列别名始终保留原始大小写
+	PreserveCase bool
+	// Lenient 为 true 时遇到语法错误不返回 error，而是在错误恢复后的语法树上继续提取，
+	// 全部错误记录在 SqlParseResult.Errors 中，结果可能不完整（出错的语句经常被整体丢弃）；
+	// 默认在第一个语法错误处停止分析（不做错误恢复），返回该错误（*ParseError）
+	Lenient bool
+	// Encoding sql 的源编码，见 Encoding* 常量，为空时按 UTF-8 处理。
+	// 非 UTF-8 的编码先转为 UTF-8 再解析；含有非法字节时返回 *InvalidEncodingError
+	Encoding string
+}
+
+// fold 按选项规范化表名、别名
+func (o ParseOptions) fold(name string) string {
+	if o.PreserveCase {
+		return name
+	}
+	return strings.ToLower(name)
+}
+
+// ParseSQL 按默认选项解析 sql，见 ParseSQLWith
+func ParseSQL(sql string) (*SqlParseResult, error) {
+	return ParseSQLWith(sql, ParseOptions{})
+}
+
+// ParseSQLWith 解析 sql，提取表、列、GROUP BY、ORDER BY、LIMIT 等信息，
+// 语法错误时返回第一个错误（*ParseError），opts.Lenient 时错误记录在结果的 Errors 中。
+// 与 ParseParams 相同，参数 ?、:name 可以出现在任何允许字面量或变量的位置
+func ParseSQLWith(sql string, opts ParseOptions) (*SqlParseResult, error) {
+	l, err := parseSQL(sql, opts)
+	if err != nil {
+		return nil, err
+	}
+	return l.result, nil
+}
+
+// ToJSON 把解析结果序列化为 JSON，供编辑器、可视化等工具使用。
+// 字段名取 json 标签并保持向后兼容；列表字段没有内容时为 []，Limit、Insert、Update 等可选项为 null
+func (r *SqlParseResult) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func parseSQL(sql string, opts ParseOptions) (*sqlListener, error) {
+	sql, err := decodeSQL(sql, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	// EXPLAIN ANALYZE、FORMAT=JSON 等写法语法不支持，去掉前缀后解析被解释的语句
+	if e, ok := parseExplain(sql); ok && e.explainable() {
+		// sql 已经解码，被解释的语句不再按原编码解码
+		inner := opts
+		inner.Encoding = ""
+		l, err := parseSQL(e.Statement, inner)
+		if err != nil {
+			return nil, shiftError(err, sql, e.StatementStart)
+		}
+		for i, err := range l.result.Errors {
+			l.result.Errors[i] = shiftError(err, sql, e.StatementStart).(*ParseError)
+		}
+		l.result.StatementType = StatementExplain
+		l.result.Explain = e
+		l.result.Directives = ParseDirectives(sql)
+		return l, nil
+	}
+
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	stream := antlr.NewCommonTokenStream(&paramSource{Lexer: lexer}, antlr.TokenDefaultChannel)
+	p := NewMySqlParser(stream)
+
+	errs := &syntaxErrorListener{bail: !opts.Lenient}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errs)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errs)
+
+	l := &sqlListener{
+		result:     &SqlParseResult{Tables: []string{}, Columns: []ColumnInfo{}, GroupBy: []string{}, GroupByItems: []GroupByInfo{}, OrderBy: []OrderByInfo{}, Joins: []JoinInfo{}, Where: []string{}, Predicates: []PredicateInfo{}, SubQueries: []SubQueryInfo{}, With: []WithInfo{}, Unions: []UnionInfo{}, Errors: []*ParseError{}},
+		tables:     map[string]bool{},
+		ctes:       map[string]bool{},
+		fold:       opts.fold,
+		orderDepth: -1,
+		limitDepth: -1,
+	}
+	tree := parseTree(p, errs)
+	if len(errs.errors) > 0 && !opts.Lenient {
+		return nil, errs.errors[0]
+	}
+	l.result.Errors = append(l.result.Errors, errs.errors...)
+	if err := l.walk(tree); err != nil {
+		return nil, err
+	}
+	l.dropCteTables()
+	l.resolveAliases()
+	stream.Fill()
+	l.result.Directives = collectDirectives(sql, stream.GetAllTokens())
+	return l, nil
+}
+
+// walk 遍历语法树。错误恢复后的语法树可能缺少节点，提取时出现的 panic 转换为 error 返回
+func (l *sqlListener) walk(tree antlr.Tree) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("extract from malformed sql: %v", r)
+		}
+	}()
+	antlr.ParseTreeWalkerDefault.Walk(l, tree)
+	return nil
+}
+
+// sqlListener 填充 SqlParseResult
+type sqlListener struct {
+	*BaseMySqlParserListener
+	result *SqlParseResult
+	tables map[string]bool
+	ctes   map[string]bool     // CTE 名称，经过 fold
+	fold   func(string) string // 规范化表名、别名，见 ParseOptions.PreserveCase
+
+	depth      int  // 当前查询的嵌套深度，最外层查询为 1
+	window     int  // 位于窗口函数 OVER 中
+	columns    bool // 已取得最外层查询的列
+	from       bool // 已取得最外层查询的 FROM
+	where      bool // 已取得最外层查询的 WHERE
+	orderDepth int  // 已记录的 ORDER BY 所在深度
+	limitDepth int  // 已记录的 LIMIT 所在深度
+	recursive  bool // 位于 WITH RECURSIVE 中
+	union      bool // 已取得最外层 UNION 的 ORDER BY、LIMIT
+
+	elements []ISelectElementContext // 最外层查询的 SELECT 列表
+	sources  []tableSource           // 最外层查询 FROM 中的表来源
+}
+
+func (l *sqlListener) EnterQuerySpecification(ctx *QuerySpecificationContext) { l.depth++ }
+func (l *sqlListener) ExitQuerySpecification(ctx *QuerySpecificationContext)  { l.depth-- }
+func (l *sqlListener) EnterQuerySpecificationNointo(ctx *QuerySpecificationNointoContext) {
+	l.depth++
+}
+func (l *sqlListener) ExitQuerySpecificationNointo(ctx *QuerySpecificationNointoContext) {
+	l.depth--
+}
+func (l *sqlListener) EnterOverClause(ctx *OverClauseContext) { l.window++ }
+func (l *sqlListener) ExitOverClause(ctx *OverClauseContext)  { l.window-- }
+
+func (l *sqlListener) EnterTableName(ctx *TableNameContext) {
+	// DELETE t1, t2 FROM ... 中的目标可以是别名，真正的表在 FROM 中
+	if _, ok := ctx.GetParent().(*MultipleDeleteStatementContext); ok {
+		return
+	}
+	name := l.fold(unquoteName(ctx.GetText()))
+	if !l.tables[name] {
+		l.tables[name] = true
+		l.result.Tables = append(l.result.Tables, name)
+	}
+}
+
+func (l *sqlListener) EnterSelectElements(ctx *SelectElementsContext) {
+	if l.depth != 1 || l.columns {
+		return
+	}
+	l.columns = true
+	l.elements = ctx.AllSelectElement()
+	if ctx.STAR() != nil {
+		l.result.Columns = append(l.result.Columns, ColumnInfo{Expr: "*", Name: "*"})
+	}
+	for _, element := range ctx.AllSelectElement() {
+		l.result.Columns = append(l.result.Columns, selectColumn(element))
+	}
+}
+
+func selectColumn(element ISelectElementContext) ColumnInfo {
+	col := ColumnInfo{Expr: originalText(element)}
+	switch e := element.(type) {
+	case *SelectStarElementContext:
+		col.Table = unquoteName(e.FullId().GetText())
+		col.Name = "*"
+	case *SelectColumnElementContext:
+		col.Expr = originalText(e.FullColumnName())
+		if !isStringColumn(e.FullColumnName()) {
+			col.Table, col.Name = splitColumnName(e.FullColumnName().GetText())
+		}
+		col.Alias = uidText(e.Uid())
+	case *SelectFunctionElementContext:
+		col.Expr = originalText(e.FunctionCall())
+		col.Alias = uidText(e.Uid())
+	case *SelectExpressionElementContext:
+		col.Expr = originalText(e.Expression())
+		col.Alias = uidText(e.Uid())
+	}
+	if call := functionCall(element); call != nil {
+		col.IsFunction = true
+		col.FunctionName = functionName(call)
+	}
+	if agg := findAggregate(element); agg != nil {
+		col.IsAggregate = true
+		col.Function = strings.ToUpper(agg.GetStart().GetText())
+		col.Distinct = agg.GetAggregator() != nil && agg.GetAggregator().GetTokenType() == MySqlParserDISTINCT
+	}
+	return col
+}
+
+// functionCall SELECT 列表中的一项（不含别名）只是一个函数调用时返回该调用
+func functionCall(element ISelectElementContext) IFunctionCallContext {
+	var node antlr.Tree
+	switch e := element.(type) {
+	case *SelectFunctionElementContext:
+		return e.FunctionCall()
+	case *SelectExpressionElementContext:
+		node = e.Expression()
+	default:
+		return nil
+	}
+	for node != nil {
+		if call, ok := node.(IFunctionCallContext); ok {
+			return call
+		}
+		if node.GetChildCount() != 1 {
+			return nil
+		}
+		node = node.GetChild(0)
+	}
+	return nil
+}
+
+// functionName 函数名，大写；自定义函数带库名时为 db.fn 形式
+func functionName(call IFunctionCallContext) string {
+	if udf, ok := call.(*UdfFunctionCallContext); ok {
+		return strings.ToUpper(unquoteName(udf.FullId().GetText()))
+	}
+	return strings.ToUpper(call.GetStart().GetText())
+}
+
+// findAggregate 按出现顺序返回第一个聚合函数，不进入子查询，跳过带 OVER 的窗口函数
+func findAggregate(node antlr.Tree) *AggregateWindowedFunctionContext {
+	if agg, ok := node.(*AggregateWindowedFunctionContext); ok && agg.OverClause() == nil {
+		return agg
+	}
+	if subquerySelect(node) != nil {
+		return nil
+	}
+	for _, child := range node.GetChildren() {
+		if agg := findAggregate(child); agg != nil {
+			return agg
+		}
+	}
+	return nil
+}
+
+func (l *sqlListener) EnterInnerJoin(ctx *InnerJoinContext) {
+	join := l.joinInfo(JoinInner, ctx.TableSourceItem(), ctx.Expression(), ctx.UidList())
+	if ctx.CROSS() != nil {
+		join.Type = JoinCross
+	} else if typ := misparsedJoin(ctx); typ != "" {
+		join.Type = typ
+	}
+	l.result.Joins = append(l.result.Joins, join)
+}
+
+// atomAlias 返回表的别名。语法允许不带 AS 的 LEFT、RIGHT 作为别名，a LEFT JOIN b 会被解析为
+// 别名为 LEFT 的 a 与 b 做 INNER JOIN；二者在 MySQL 中是保留字，不能直接作为别名，
+// 这种情况返回空别名，并返回实际的 JOIN 类型
+func atomAlias(item *AtomTableItemContext) (string, string) {
+	alias := item.GetAlias()
+	if alias == nil {
+		return "", ""
+	}
+	if item.AS() == nil {
+		switch strings.ToUpper(alias.GetText()) {
+		case JoinLeft:
+			return "", JoinLeft
+		case JoinRight:
+			return "", JoinRight
+		}
+	}
+	return uidText(alias), ""
+}
+
+// misparsedJoin 返回被误解析为 INNER JOIN 的 LEFT/RIGHT JOIN 的实际类型，见 atomAlias
+func misparsedJoin(ctx *InnerJoinContext) string {
+	var prev antlr.Tree
+	for _, child := range ctx.GetParent().GetChildren() {
+		if child == antlr.Tree(ctx) {
+			break
+		}
+		prev = child
+	}
+	var item ITableSourceItemContext
+	switch p := prev.(type) {
+	case ITableSourceItemContext:
+		item = p
+	case *InnerJoinContext:
+		item = p.TableSourceItem()
+	case *StraightJoinContext:
+		item = p.TableSourceItem()
+	case *OuterJoinContext:
+		item = p.TableSourceItem()
+	case *NaturalJoinContext:
+		item = p.TableSourceItem()
+	}
+	if atom, ok := item.(*AtomTableItemContext); ok {
+		_, typ := atomAlias(atom)
+		return typ
+	}
+	return ""
+}
+
+func (l *sqlListener) EnterStraightJoin(ctx *StraightJoinContext) {
+	l.result.Joins = append(l.result.Joins, l.joinInfo(JoinStraight, ctx.TableSourceItem(), ctx.Expression(), nil))
+}
+
+func (l *sqlListener) EnterOuterJoin(ctx *OuterJoinContext) {
+	typ := JoinLeft
+	if ctx.RIGHT() != nil {
+		typ = JoinRight
+	}
+	l.result.Joins = append(l.result.Joins, l.joinInfo(typ, ctx.TableSourceItem(), ctx.Expression(), ctx.UidList()))
+}
+
+func (l *sqlListener) EnterNaturalJoin(ctx *NaturalJoinContext) {
+	typ := JoinInner
+	if ctx.LEFT() != nil {
+		typ = JoinLeft
+	} else if ctx.RIGHT() != nil {
+		typ = JoinRight
+	}
+	join := l.joinInfo(typ, ctx.TableSourceItem(), nil, nil)
+	join.Natural = true
+	l.result.Joins = append(l.result.Joins, join)
+}
+
+func (l *sqlListener) joinInfo(typ string, item ITableSourceItemContext, on IExpressionContext, using IUidListContext) JoinInfo {
+	join := JoinInfo{Type: typ, Using: []string{}}
+	switch t := item.(type) {
+	case *AtomTableItemContext:
+		join.Table = l.fold(unquoteName(t.TableName().GetText()))
+		join.Alias, _ = atomAlias(t)
+	case *SubqueryTableItemContext:
+		join.Alias = uidText(t.GetAlias())
+	}
+	if on != nil {
+		join.Condition = originalText(on)
+	}
+	if list, ok := using.(*UidListContext); ok {
+		for _, uid := range list.AllUid() {
+			join.Using = append(join.Using, uidText(uid))
+		}
+		join.Condition = "USING (" + strings.Join(join.Using, ", ") + ")"
+	}
+	return join
+}
+
+func (l *sqlListener) EnterFromClause(ctx *FromClauseContext) {
+	if l.depth != 1 {
+		return
+	}
+	if !l.from && ctx.TableSources() != nil {
+		l.from = true
+		l.sources = collectSources(ctx.TableSources(), nil, l.fold)
+	}
+	l.setWhere(ctx.GetWhereExpr())
+}
+
+// setWhere 记录最外层语句的 WHERE，只取第一个
+func (l *sqlListener) setWhere(expr IExpressionContext) {
+	if expr == nil || l.where {
+		return
+	}
+	l.where = true
+	l.result.WhereExpr = originalText(expr)
+	for _, cond := range splitAnd(expr) {
+		l.result.Where = append(l.result.Where, originalText(cond))
+		l.result.Predicates = append(l.result.Predicates, predicateInfo(cond))
+	}
+}
+
+// splitAnd 按优先级展开顶层的 AND（含 &&），a OR b AND c 整体是一个 OR 条件；
+// 括号和子查询内部不拆分
+func splitAnd(expr IExpressionContext) []IExpressionContext {
+	chain := newLogicalChain(expr)
+	if op, _ := chain.split(); op == "AND" {
+		return chain.operands
+	}
+	return []IExpressionContext{expr}
+}
+
+func (l *sqlListener) EnterGroupByClause(ctx *GroupByClauseContext) {
+	if l.depth != 1 {
+		return
+	}
+	for _, item := range ctx.AllGroupByItem() {
+		l.result.GroupBy = append(l.result.GroupBy, originalText(item))
+		g := GroupByInfo{Expr: originalText(item)}
+		g.Table, g.Column = columnRef(item.(*GroupByItemContext).Expression())
+		l.result.GroupByItems = append(l.result.GroupByItems, g)
+	}
+}
+
+// UNION 整体的 ORDER BY/LIMIT 位于各个查询之外（深度 0），优先于查询内的
+func (l *sqlListener) EnterOrderByClause(ctx *OrderByClauseContext) {
+	if l.window > 0 || l.depth > 1 || (l.orderDepth >= 0 && l.depth >= l.orderDepth) {
+		return
+	}
+	l.orderDepth = l.depth
+	l.result.OrderBy = orderByItems(ctx)
+}
+
+func orderByItems(ctx *OrderByClauseContext) []OrderByInfo {
+	items := []OrderByInfo{}
+	for _, item := range ctx.AllOrderByExpression() {
+		e := item.(*OrderByExpressionContext)
+		o := OrderByInfo{Expr: originalText(e.Expression()), Desc: e.DESC() != nil}
+		o.Table, o.Column = columnRef(e.Expression())
+		items = append(items, o)
+	}
+	return items
+}
+
+func (l *sqlListener) EnterLimitClause(ctx *LimitClauseContext) {
+	if l.depth > 1 || (l.limitDepth >= 0 && l.depth >= l.limitDepth) {
+		return
+	}
+	l.limitDepth = l.depth
+	l.result.Limit = limitInfo(ctx)
+}
+
+// limitInfo 从词法单元读取 LIMIT 的值
+func limitInfo(ctx *LimitClauseContext) *LimitInfo {
+	return limitAfter(ctx.GetParser().GetTokenStream(), ctx.LIMIT())
+}
+
+// limitAfter 读取 LIMIT 关键字之后的值
+func limitAfter(stream antlr.TokenStream, keyword antlr.TerminalNode) *LimitInfo {
+	i := keyword.GetSymbol().GetTokenIndex() + 1
+	next := func() antlr.Token {
+		for ; i < stream.Size(); i++ {
+			t := stream.Get(i)
+			if t.GetChannel() == antlr.TokenDefaultChannel {
+				i++
+				return t
+			}
+		}
+		return nil
+	}
+
+	count := next()
+	limit := &LimitInfo{Count: limitValue(count), OffsetParam: -1, CountParam: paramIndex(stream, count)}
+	switch t := next(); {
+	case t == nil:
+	case t.GetTokenType() == MySqlParserCOMMA:
+		offset := next()
+		limit.Offset, limit.OffsetParam = limit.Count, limit.CountParam
+		limit.Count, limit.CountParam = limitValue(offset), paramIndex(stream, offset)
+	case t.GetTokenType() == MySqlParserOFFSET:
+		offset := next()
+		limit.Offset, limit.OffsetParam = limitValue(offset), paramIndex(stream, offset)
+	}
+	return limit
+}
+
+// paramIndex t 为占位符 ? 时返回它在全部占位符中的序号，否则返回 -1
+func paramIndex(stream antlr.TokenStream, t antlr.Token) int {
+	if t == nil || t.GetText() != "?" {
+		return -1
+	}
+	n := 0
+	for i := 0; i < t.GetTokenIndex(); i++ {
+		if isParam(stream.Get(i)) {
+			n++
+		}
+	}
+	return n
+}
+
+// limitValue LIMIT 中的数值，不是数字字面量（如 ? 或变量）时返回 -1
+func limitValue(t antlr.Token) int64 {
+	if t == nil {
+		return -1
+	}
+	n, err := strconv.ParseInt(t.GetText(), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// isStringColumn 判断列名是否为字符串常量：语法允许 SELECT 'abc' 中的 'abc' 作为列名，
+// 它不引用任何列
+func isStringColumn(ctx IFullColumnNameContext) bool {
+	return ctx.GetStart() == ctx.GetStop() && ctx.GetStart().GetTokenType() == MySqlParserSTRING_LITERAL
+}
+
+// splitColumnName 拆分 t.col、db.t.col 形式的列名，返回限定符和列名
+func splitColumnName(name string) (string, string) {
+	name = unquoteName(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
@@ -0,0 +1,369 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// mustParse 按默认选项解析，出错时终止测试
+func mustParse(t *testing.T, sql string) *SqlParseResult {
+	t.Helper()
+	result, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("ParseSQL(%q): %v", sql, err)
+	}
+	return result
+}
+
+func TestParseSQL(t *testing.T) {
+	cases := []struct {
+		sql     string
+		stmt    string
+		tables  []string
+		columns []string // 各列的 Expr
+		groupBy []string
+		orderBy []string
+		limit   *LimitInfo
+	}{
+		{
+			sql:     "SELECT a.id, b.name AS n, COUNT(*) FROM t1 a JOIN db.t2 b ON a.id = b.aid GROUP BY a.id, b.name ORDER BY n DESC, a.id LIMIT 10, 20",
+			stmt:    StatementSelect,
+			tables:  []string{"t1", "db.t2"},
+			columns: []string{"a.id", "b.name", "COUNT(*)"},
+			groupBy: []string{"a.id", "b.name"},
+			orderBy: []string{"n", "a.id"},
+			limit:   &LimitInfo{Offset: 10, Count: 20, OffsetParam: -1, CountParam: -1},
+		},
+		{
+			sql:     "select * from `Orders` where id in (select order_id from items)",
+			stmt:    StatementSelect,
+			tables:  []string{"orders", "items"},
+			columns: []string{"*"},
+			groupBy: []string{},
+			orderBy: []string{},
+		},
+		{
+			sql:     "SELECT 1",
+			stmt:    StatementSelect,
+			tables:  []string{},
+			columns: []string{"1"},
+			groupBy: []string{},
+			orderBy: []string{},
+		},
+		{
+			sql:     "SET NAMES utf8mb4",
+			stmt:    StatementOther,
+			tables:  []string{},
+			columns: []string{},
+			groupBy: []string{},
+			orderBy: []string{},
+		},
+		{
+			sql:     "",
+			tables:  []string{},
+			columns: []string{},
+			groupBy: []string{},
+			orderBy: []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			if result.StatementType != c.stmt {
+				t.Errorf("StatementType = %q, want %q", result.StatementType, c.stmt)
+			}
+			if !reflect.DeepEqual(result.Tables, c.tables) {
+				t.Errorf("Tables = %v, want %v", result.Tables, c.tables)
+			}
+			columns := []string{}
+			for _, col := range result.Columns {
+				columns = append(columns, col.Expr)
+			}
+			if !reflect.DeepEqual(columns, c.columns) {
+				t.Errorf("Columns = %v, want %v", columns, c.columns)
+			}
+			if !reflect.DeepEqual(result.GroupBy, c.groupBy) {
+				t.Errorf("GroupBy = %v, want %v", result.GroupBy, c.groupBy)
+			}
+			orderBy := []string{}
+			for _, o := range result.OrderBy {
+				orderBy = append(orderBy, o.Expr)
+			}
+			if !reflect.DeepEqual(orderBy, c.orderBy) {
+				t.Errorf("OrderBy = %v, want %v", orderBy, c.orderBy)
+			}
+			if !reflect.DeepEqual(result.Limit, c.limit) {
+				t.Errorf("Limit = %+v, want %+v", result.Limit, c.limit)
+			}
+		})
+	}
+}
+
+func TestParseSQLColumns(t *testing.T) {
+	result := mustParse(t, "SELECT a.id, b.name AS n, COUNT(*) AS cnt FROM t1 a JOIN t2 b ON a.id = b.aid")
+	want := []ColumnInfo{
+		{Expr: "a.id", Table: "t1", TableAlias: "a", Name: "id"},
+		{Expr: "b.name", Table: "t2", TableAlias: "b", Name: "name", Alias: "n"},
+		{Expr: "COUNT(*)", Alias: "cnt", IsFunction: true, FunctionName: "COUNT", IsAggregate: true, Function: "COUNT"},
+	}
+	if !reflect.DeepEqual(result.Columns, want) {
+		t.Errorf("Columns = %+v\nwant %+v", result.Columns, want)
+	}
+}
+
+func TestAggregateColumns(t *testing.T) {
+	cases := []struct {
+		expr      string
+		isFunc    bool
+		funcName  string
+		aggregate bool
+		function  string
+		distinct  bool
+	}{
+		{"COUNT(DISTINCT id)", true, "COUNT", true, "COUNT", true},
+		{"ROUND(AVG(x), 2)", true, "ROUND", true, "AVG", false},
+		{"SUM(x) OVER (PARTITION BY g)", true, "SUM", false, "", false},
+		{"MIN(x) OVER w", true, "MIN", false, "", false},
+		{"a + MAX(b)", false, "", true, "MAX", false},
+		{"UPPER(name)", true, "UPPER", false, "", false},
+		{"(SELECT COUNT(*) FROM s)", false, "", false, "", false},
+		{"GROUP_CONCAT(DISTINCT n)", true, "GROUP_CONCAT", true, "GROUP_CONCAT", true},
+		{"id", false, "", false, "", false},
+	}
+	for _, c := range cases {
+		sql := "SELECT " + c.expr + " FROM t WINDOW w AS (ORDER BY id)"
+		result := mustParse(t, sql)
+		if len(result.Columns) != 1 {
+			t.Errorf("%q: len(Columns) = %d, want 1", sql, len(result.Columns))
+			continue
+		}
+		col := result.Columns[0]
+		if col.Expr != c.expr || col.IsFunction != c.isFunc || col.FunctionName != c.funcName ||
+			col.IsAggregate != c.aggregate || col.Function != c.function || col.Distinct != c.distinct {
+			t.Errorf("%q: Columns[0] = %+v", sql, col)
+		}
+	}
+}
+
+func TestQualifiedTableColumns(t *testing.T) {
+	cases := []struct {
+		sql   string
+		table string
+		alias string
+	}{
+		{"SELECT t.a FROM db.t", "db.t", ""},
+		{"SELECT db.t.a FROM db.t", "db.t", ""},
+		{"SELECT T.a FROM DB.t", "db.t", ""},
+		{"SELECT t.a FROM db.t x", "t", ""},
+		{"SELECT t.a FROM db.t JOIN db2.s ON 1 = 1", "db.t", ""},
+	}
+	for _, c := range cases {
+		result := mustParse(t, c.sql)
+		if col := result.Columns[0]; col.Table != c.table || col.TableAlias != c.alias {
+			t.Errorf("%q: Table, TableAlias = %q, %q, want %q, %q", c.sql, col.Table, col.TableAlias, c.table, c.alias)
+		}
+	}
+}
+
+func TestStringColumns(t *testing.T) {
+	result := mustParse(t, `SELECT 'abc', "x" AS y, a, 1 FROM t`)
+	want := []ColumnInfo{
+		{Expr: "'abc'"},
+		{Expr: `"x"`, Alias: "y"},
+		{Expr: "a", Table: "t", Name: "a"},
+		{Expr: "1"},
+	}
+	if !reflect.DeepEqual(result.Columns, want) {
+		t.Errorf("Columns = %+v, want %+v", result.Columns, want)
+	}
+}
+
+func TestParseSQLSyntaxError(t *testing.T) {
+	for _, sql := range []string{"SELECT FROM", "SELEC 1", "SELECT a FROM t WHERE"} {
+		if _, err := ParseSQL(sql); err == nil {
+			t.Errorf("ParseSQL(%q) returned no error", sql)
+		}
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	out, err := mustParse(t, "SELECT a FROM t").ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"statementType", "tables", "columns", "limit", "joins", "where"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("JSON has no %q field", key)
+		}
+	}
+	if fields["limit"] != nil {
+		t.Errorf("limit = %v, want null", fields["limit"])
+	}
+}
+
+func TestLimit(t *testing.T) {
+	cases := []struct {
+		sql   string
+		limit *LimitInfo
+	}{
+		{"SELECT * FROM t LIMIT 10", &LimitInfo{Offset: 0, Count: 10, OffsetParam: -1, CountParam: -1}},
+		{"SELECT * FROM t LIMIT 20, 10", &LimitInfo{Offset: 20, Count: 10, OffsetParam: -1, CountParam: -1}},
+		{"SELECT * FROM t LIMIT 10 OFFSET 20", &LimitInfo{Offset: 20, Count: 10, OffsetParam: -1, CountParam: -1}},
+		{"SELECT * FROM t LIMIT ?", &LimitInfo{Offset: 0, Count: -1, OffsetParam: -1, CountParam: 0}},
+		{"SELECT * FROM t LIMIT ?, ?", &LimitInfo{Offset: -1, Count: -1, OffsetParam: 0, CountParam: 1}},
+		{"SELECT * FROM t LIMIT ? OFFSET ?", &LimitInfo{Offset: -1, Count: -1, OffsetParam: 1, CountParam: 0}},
+		{"SELECT * FROM t WHERE a = '?' LIMIT 10 OFFSET ?", &LimitInfo{Offset: -1, Count: 10, OffsetParam: 0, CountParam: -1}},
+		{"SELECT * FROM (SELECT * FROM s LIMIT ?) x LIMIT ?, 5", &LimitInfo{Offset: -1, Count: 5, OffsetParam: 1, CountParam: -1}},
+		{"SELECT * FROM t LIMIT @n", &LimitInfo{Offset: 0, Count: -1, OffsetParam: -1, CountParam: -1}},
+		{"DELETE FROM t ORDER BY id LIMIT ?", &LimitInfo{Offset: 0, Count: -1, OffsetParam: -1, CountParam: 0}},
+		{"SELECT * FROM t WHERE id = ? LIMIT ?", &LimitInfo{Offset: 0, Count: -1, OffsetParam: -1, CountParam: 1}},
+		{"SELECT * FROM t WHERE a = :a AND b IN (?, @b) LIMIT :n, ?", &LimitInfo{Offset: -1, Count: -1, OffsetParam: -1, CountParam: 1}},
+		{"SELECT * FROM t", nil},
+	}
+	for _, c := range cases {
+		result := mustParse(t, c.sql)
+		if !reflect.DeepEqual(result.Limit, c.limit) {
+			t.Errorf("%q: Limit = %+v, want %+v", c.sql, result.Limit, c.limit)
+		}
+	}
+}
+
+func TestPreserveCase(t *testing.T) {
+	cases := []struct {
+		sql      string
+		preserve bool
+		tables   []string
+		columns  []string // 各列的 Table
+		joins    []string // 各 JOIN 的 Table
+	}{
+		{
+			sql:     "SELECT O.id, I.qty FROM `Orders` O JOIN Items I ON I.oid = O.id",
+			tables:  []string{"orders", "items"},
+			columns: []string{"orders", "items"},
+			joins:   []string{"items"},
+		},
+		{
+			sql:      "SELECT O.id, I.qty FROM `Orders` O JOIN Items I ON I.oid = O.id",
+			preserve: true,
+			tables:   []string{"Orders", "Items"},
+			columns:  []string{"Orders", "Items"},
+			joins:    []string{"Items"},
+		},
+		{
+			// 区分大小写时 orders 与 Orders 是两张表，o 不是 O 的别名
+			sql:      "SELECT O.id, o.id FROM Orders O JOIN orders o2 ON o2.id = O.id",
+			preserve: true,
+			tables:   []string{"Orders", "orders"},
+			columns:  []string{"Orders", "o"},
+			joins:    []string{"orders"},
+		},
+		{
+			sql:      "WITH Recent AS (SELECT id FROM Orders) SELECT Recent.id FROM Recent",
+			preserve: true,
+			tables:   []string{"Orders"},
+			columns:  []string{TableCte},
+			joins:    []string{},
+		},
+		{
+			sql:     "WITH Recent AS (SELECT id FROM Orders) SELECT recent.id FROM RECENT",
+			tables:  []string{"orders"},
+			columns: []string{TableCte},
+			joins:   []string{},
+		},
+	}
+	for _, c := range cases {
+		result, err := ParseSQLWith(c.sql, ParseOptions{PreserveCase: c.preserve})
+		if err != nil {
+			t.Errorf("%q: %v", c.sql, err)
+			continue
+		}
+		if !reflect.DeepEqual(result.Tables, c.tables) {
+			t.Errorf("%q (preserve=%v): Tables = %v, want %v", c.sql, c.preserve, result.Tables, c.tables)
+		}
+		columns := []string{}
+		for _, col := range result.Columns {
+			columns = append(columns, col.Table)
+		}
+		if !reflect.DeepEqual(columns, c.columns) {
+			t.Errorf("%q (preserve=%v): column tables = %v, want %v", c.sql, c.preserve, columns, c.columns)
+		}
+		joins := []string{}
+		for _, j := range result.Joins {
+			joins = append(joins, j.Table)
+		}
+		if !reflect.DeepEqual(joins, c.joins) {
+			t.Errorf("%q (preserve=%v): join tables = %v, want %v", c.sql, c.preserve, joins, c.joins)
+		}
+	}
+
+	for _, preserve := range []bool{false, true} {
+		result, err := ParseSQLWith("INSERT INTO Db.`Users` (Name) VALUES ('a')", ParseOptions{PreserveCase: preserve})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "db.users"
+		if preserve {
+			want = "Db.Users"
+		}
+		if result.Insert.Table != want || !reflect.DeepEqual(result.Insert.Columns, []string{"Name"}) {
+			t.Errorf("preserve=%v: Insert = %+v, want table %q", preserve, result.Insert, want)
+		}
+	}
+}
+
+func TestParseSQLErrors(t *testing.T) {
+	sql := "WITH a AS (SELECT 1) SELECT * FROM a, ;\nSELECT b FROM s;\nSELEC 1"
+
+	_, err := ParseSQL(sql)
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("ParseSQL: err = %v, want *ParseError", err)
+	}
+	if pe.Line != 1 || pe.Column != 38 || pe.Token != ";" {
+		t.Errorf("ParseSQL: err = %+v, want line 1:38 at ';'", pe)
+	}
+
+	result, err := ParseSQLWith(sql, ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("ParseSQLWith(Lenient): %v", err)
+	}
+	if len(result.Errors) != 2 || *result.Errors[0] != *pe || result.Errors[1].Line != 3 {
+		t.Errorf("Errors = %+v, want the first error and one at line 3", result.Errors)
+	}
+	if !reflect.DeepEqual(result.Tables, []string{"s"}) {
+		t.Errorf("Tables = %v, want [s]", result.Tables)
+	}
+
+	result, err = ParseSQLWith("SELECT 1", ParseOptions{Lenient: true})
+	if err != nil || len(result.Errors) != 0 {
+		t.Errorf("ParseSQLWith(valid, Lenient) = %+v, %v", result.Errors, err)
+	}
+}
+
+// 严格模式在第一个错误处停止分析，不再做错误恢复
+func TestParseTreeBail(t *testing.T) {
+	for _, bail := range []bool{false, true} {
+		lexer := NewMySqlLexer(antlr.NewInputStream("WITH a AS (SELECT 1) SELECT * FROM a, ; SELECT b FROM s; SELEC 1"))
+		p := NewMySqlParser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+		errs := &syntaxErrorListener{bail: bail}
+		lexer.RemoveErrorListeners()
+		lexer.AddErrorListener(errs)
+		p.RemoveErrorListeners()
+		p.AddErrorListener(errs)
+
+		tree := parseTree(p, errs)
+		if bail && (tree != nil || len(errs.errors) != 1) {
+			t.Errorf("bail: tree = %v, %d errors, want nil and 1 error", tree, len(errs.errors))
+		}
+		if !bail && (tree == nil || len(errs.errors) != 2) {
+			t.Errorf("no bail: %d errors, want a tree and all errors", len(errs.errors))
+		}
+	}
+}
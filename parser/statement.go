@@ -0,0 +1,52 @@
+package parser
+
+// 语句类型
+const (
+	StatementSelect      = "SELECT"
+	StatementInsert      = "INSERT"
+	StatementReplace     = "REPLACE"
+	StatementUpdate      = "UPDATE"
+	StatementDelete      = "DELETE"
+	StatementCreateTable = "CREATE TABLE"
+	StatementAlterTable  = "ALTER TABLE"
+	StatementCreateView  = "CREATE VIEW"
+	StatementExplain     = "EXPLAIN" // EXPLAIN/DESCRIBE 一条语句，其他字段取自被解释的语句
+	StatementOther       = "OTHER"
+)
+
+// EnterSqlStatement 按第一条语句确定 StatementType
+func (l *sqlListener) EnterSqlStatement(ctx *SqlStatementContext) {
+	if l.result.StatementType != "" {
+		return
+	}
+	l.result.StatementType = StatementOther
+	switch s := ctx.GetChild(0).(type) {
+	case *DmlStatementContext:
+		// 以 WITH 开头的语句中第一个子节点是 WITH 子句，见 parseRoot
+		child := s.GetChild(0)
+		if _, ok := child.(*WithClauseContext); ok && s.GetChildCount() > 1 {
+			child = s.GetChild(1)
+		}
+		switch child.(type) {
+		case ISelectStatementContext:
+			l.result.StatementType = StatementSelect
+		case *InsertStatementContext:
+			l.result.StatementType = StatementInsert
+		case *ReplaceStatementContext:
+			l.result.StatementType = StatementReplace
+		case IUpdateStatementContext:
+			l.result.StatementType = StatementUpdate
+		case IDeleteStatementContext:
+			l.result.StatementType = StatementDelete
+		}
+	case *DdlStatementContext:
+		switch s.GetChild(0).(type) {
+		case ICreateTableContext:
+			l.result.StatementType = StatementCreateTable
+		case *AlterTableContext:
+			l.result.StatementType = StatementAlterTable
+		case *CreateViewContext:
+			l.result.StatementType = StatementCreateView
+		}
+	}
+}
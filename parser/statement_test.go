@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatementType(t *testing.T) {
+	cases := []struct {
+		sql  string
+		stmt string
+	}{
+		{"SELECT 1", StatementSelect},
+		{"(SELECT a FROM t) UNION (SELECT a FROM s)", StatementSelect},
+		{"INSERT INTO t VALUES (1)", StatementInsert},
+		{"REPLACE INTO t VALUES (1)", StatementReplace},
+		{"UPDATE t SET a = 1", StatementUpdate},
+		{"UPDATE t JOIN s ON t.id = s.id SET t.a = s.a", StatementUpdate},
+		{"DELETE FROM t WHERE id = 1", StatementDelete},
+		{"DELETE t FROM t JOIN s ON t.id = s.id", StatementDelete},
+		{"CREATE TABLE t (id INT)", StatementCreateTable},
+		{"ALTER TABLE t ADD COLUMN a INT", StatementAlterTable},
+		{"CREATE VIEW v AS SELECT 1", StatementCreateView},
+		{"EXPLAIN SELECT * FROM t", StatementExplain},
+		{"DROP TABLE t", StatementOther},
+		{"SELECT 1; DELETE FROM t", StatementSelect},
+	}
+	for _, c := range cases {
+		if got := mustParse(t, c.sql).StatementType; got != c.stmt {
+			t.Errorf("%q: StatementType = %q, want %q", c.sql, got, c.stmt)
+		}
+	}
+}
+
+func TestReplaceInsert(t *testing.T) {
+	cases := []struct {
+		sql  string
+		want InsertInfo
+	}{
+		{
+			sql: "REPLACE INTO t (a, b) VALUES (1, 2), (3)",
+			want: InsertInfo{Table: "t", Columns: []string{"a", "b"}, DuplicateColumns: []string{},
+				RowCount: 2, ColumnCount: 2, MismatchRows: []int{1}},
+		},
+		{
+			sql: "REPLACE t SET a = 1",
+			want: InsertInfo{Table: "t", Columns: []string{"a"}, DuplicateColumns: []string{},
+				RowCount: 1, MismatchRows: []int{}},
+		},
+		{
+			sql: "REPLACE INTO `T` SELECT * FROM s",
+			want: InsertInfo{Table: "t", Columns: []string{}, Select: true, DuplicateColumns: []string{},
+				MismatchRows: []int{}},
+		},
+	}
+	for _, c := range cases {
+		result := mustParse(t, c.sql)
+		if result.Insert == nil {
+			t.Errorf("%q: Insert = nil", c.sql)
+			continue
+		}
+		if !reflect.DeepEqual(*result.Insert, c.want) {
+			t.Errorf("%q: Insert = %+v\nwant %+v", c.sql, *result.Insert, c.want)
+		}
+	}
+}
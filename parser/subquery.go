@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// 子查询所在的位置
+const (
+	SubqueryFrom   = "FROM"   // FROM/JOIN 中的派生表
+	SubqueryWhere  = "WHERE"  // WHERE 中的 IN、EXISTS、比较等
+	SubquerySelect = "SELECT" // SELECT 列表中的标量子查询
+	SubqueryHaving = "HAVING" // HAVING 条件
+	SubqueryOther  = "OTHER"  // 其他位置，如 ORDER BY、JOIN 的 ON 条件
+)
+
+// SubQueryInfo 一个子查询
+type SubQueryInfo struct {
+	Type  string `json:"type"`  // 见 Subquery* 常量
+	Alias string `json:"alias"` // 派生表的别名，其他位置为空
+	SQL   string `json:"sql"`   // 子查询原文，不含外层括号
+	Depth int    `json:"depth"` // 嵌套深度，直接位于最外层查询中的为 1
+
+	OuterRefs   []string `json:"outerRefs"`   // 引用外层查询中表的列，如 o.id，包含更深层子查询中的引用
+	Correlation int      `json:"correlation"` // 引用的外层查询最远在几层之外，0 表示非相关子查询
+}
+
+func (l *sqlListener) EnterSubqueryTableItem(ctx *SubqueryTableItemContext) {
+	l.addSubquery(ctx, uidText(ctx.GetAlias()))
+}
+
+func (l *sqlListener) EnterSubqueryExpressionAtom(ctx *SubqueryExpressionAtomContext) {
+	l.addSubquery(ctx, "")
+}
+
+func (l *sqlListener) EnterExistsExpressionAtom(ctx *ExistsExpressionAtomContext) {
+	l.addSubquery(ctx, "")
+}
+
+func (l *sqlListener) EnterInPredicate(ctx *InPredicateContext) {
+	if ctx.SelectStatement() != nil {
+		l.addSubquery(ctx, "")
+	}
+}
+
+func (l *sqlListener) EnterSubqueryComparisonPredicate(ctx *SubqueryComparisonPredicateContext) {
+	l.addSubquery(ctx, "")
+}
+
+func (l *sqlListener) addSubquery(ctx antlr.ParserRuleContext, alias string) {
+	sub := SubQueryInfo{
+		Type:  subqueryType(ctx),
+		Alias: alias,
+		SQL:   originalText(unwrapParens(subquerySelect(ctx))),
+		Depth: subqueryDepth(ctx),
+	}
+	sub.OuterRefs, sub.Correlation = correlation(subquerySelect(ctx))
+	l.result.SubQueries = append(l.result.SubQueries, sub)
+}
+
+// unwrapParens 去掉 (SELECT ...) 外层的括号，带锁定子句时保留原样
+func unwrapParens(stmt ISelectStatementContext) antlr.ParserRuleContext {
+	p, ok := stmt.(*ParenthesisSelectContext)
+	if !ok || p.LockClause() != nil {
+		return stmt
+	}
+	expr, _ := p.QueryExpression().(*QueryExpressionContext)
+	for expr != nil && expr.QuerySpecification() == nil {
+		expr, _ = expr.QueryExpression().(*QueryExpressionContext)
+	}
+	if expr == nil {
+		return stmt
+	}
+	return expr.QuerySpecification()
+}
+
+// subquerySelect 返回子查询节点中的查询语句，node 不是子查询时返回 nil
+func subquerySelect(node antlr.Tree) ISelectStatementContext {
+	switch n := node.(type) {
+	case *SubqueryTableItemContext:
+		if n.GetParenthesisSubquery() != nil {
+			return n.GetParenthesisSubquery()
+		}
+		return n.SelectStatement()
+	case *SubqueryExpressionAtomContext:
+		return n.SelectStatement()
+	case *ExistsExpressionAtomContext:
+		return n.SelectStatement()
+	case *InPredicateContext:
+		return n.SelectStatement()
+	case *SubqueryComparisonPredicateContext:
+		return n.SelectStatement()
+	}
+	return nil
+}
+
+// subqueryType 向上查找子查询所在的子句
+func subqueryType(ctx antlr.Tree) string {
+	child := ctx
+	for node := ctx.GetParent(); node != nil; child, node = node, node.GetParent() {
+		switch n := node.(type) {
+		case *SelectElementsContext:
+			return SubquerySelect
+		case *HavingClauseContext:
+			return SubqueryHaving
+		case *FromClauseContext:
+			if n.GetWhereExpr() == child {
+				return SubqueryWhere
+			}
+			if n.TableSources() == child {
+				if _, ok := ctx.(*SubqueryTableItemContext); ok {
+					return SubqueryFrom
+				}
+			}
+			return SubqueryOther
+		case *SingleUpdateStatementContext:
+			if n.Expression() == child {
+				return SubqueryWhere
+			}
+			return SubqueryOther
+		case *MultipleUpdateStatementContext:
+			if n.Expression() == child {
+				return SubqueryWhere
+			}
+			return SubqueryOther
+		case *SingleDeleteStatementContext:
+			if n.Expression() == child {
+				return SubqueryWhere
+			}
+			return SubqueryOther
+		case *MultipleDeleteStatementContext:
+			if n.Expression() == child {
+				return SubqueryWhere
+			}
+			return SubqueryOther
+		case *QuerySpecificationContext, *QuerySpecificationNointoContext:
+			return SubqueryOther
+		}
+	}
+	return SubqueryOther
+}
+
+// subqueryDepth 子查询的嵌套深度，即包括自身在内的子查询祖先个数
+func subqueryDepth(ctx antlr.Tree) int {
+	depth := 0
+	for node := ctx; node != nil; node = node.GetParent() {
+		if subquerySelect(node) != nil {
+			depth++
+		}
+	}
+	return depth
+}
+
+// correlation 返回子查询 sel 中引用外层表的列及最远的层数。
+// 只识别带表限定的列，不带限定的列需要表结构才能判断归属，按非相关处理
+func correlation(sel ISelectStatementContext) ([]string, int) {
+	refs := []string{}
+	depth := 0
+	var walk func(node antlr.Tree)
+	walk = func(node antlr.Tree) {
+		if col, ok := node.(*FullColumnNameContext); ok {
+			qualifier, _ := splitColumnName(col.GetText())
+			if qualifier == "" {
+				return
+			}
+			if levels := outerLevels(sel, definingQuery(col, qualifier)); levels > 0 {
+				refs = appendUnique(refs, originalText(col))
+				if levels > depth {
+					depth = levels
+				}
+			}
+			return
+		}
+		for _, child := range node.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(sel)
+	return refs, depth
+}
+
+// definingQuery 由内向外查找 FROM 中定义了 qualifier 的查询（或 UPDATE、DELETE 语句），找不到时返回 nil。
+// 只用于判断列是否引用外层查询，表名和别名不区分大小写
+func definingQuery(col antlr.Tree, qualifier string) antlr.Tree {
+	for node := col.GetParent(); node != nil; node = node.GetParent() {
+		if sources, ok := querySources(node); ok {
+			if _, ok := resolveTable(qualifier, sources, strings.ToLower); ok {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// querySources 返回查询或 UPDATE、DELETE 语句中定义的表来源，node 不是这些节点时返回 false
+func querySources(node antlr.Tree) ([]tableSource, bool) {
+	var from IFromClauseContext
+	switch q := node.(type) {
+	case *QuerySpecificationContext:
+		from = q.FromClause()
+	case *QuerySpecificationNointoContext:
+		from = q.FromClause()
+	case *SingleUpdateStatementContext:
+		return []tableSource{singleSource(q.TableName(), q.Uid(), strings.ToLower)}, true
+	case *MultipleUpdateStatementContext:
+		return collectSources(q.TableSources(), nil, strings.ToLower), true
+	case *SingleDeleteStatementContext:
+		return []tableSource{singleSource(q.TableName(), nil, strings.ToLower)}, true
+	case *MultipleDeleteStatementContext:
+		return collectSources(q.TableSources(), nil, strings.ToLower), true
+	default:
+		return nil, false
+	}
+	f, ok := from.(*FromClauseContext)
+	if !ok || f.TableSources() == nil {
+		return nil, true
+	}
+	return collectSources(f.TableSources(), nil, strings.ToLower), true
+}
+
+// outerLevels 返回 query 位于子查询 sel 之外的层数，query 在 sel 内部或为 nil 时返回 0
+func outerLevels(sel antlr.Tree, query antlr.Tree) int {
+	if query == nil {
+		return 0
+	}
+	levels := 1
+	for node := sel.GetParent(); node != nil; node = node.GetParent() {
+		if node == query {
+			return levels
+		}
+		switch node.(type) {
+		case *QuerySpecificationContext, *QuerySpecificationNointoContext:
+			levels++
+		}
+	}
+	return 0
+}
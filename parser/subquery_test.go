@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubQueries(t *testing.T) {
+	cases := []struct {
+		sql  string
+		subs []SubQueryInfo // 不比较 OuterRefs、Correlation
+	}{
+		{
+			sql: "SELECT * FROM (SELECT id FROM t) AS d",
+			subs: []SubQueryInfo{
+				{Type: SubqueryFrom, Alias: "d", SQL: "SELECT id FROM t", Depth: 1},
+			},
+		},
+		{
+			sql: "SELECT * FROM t WHERE id IN (SELECT tid FROM s) AND EXISTS (SELECT 1 FROM u)",
+			subs: []SubQueryInfo{
+				{Type: SubqueryWhere, SQL: "SELECT tid FROM s", Depth: 1},
+				{Type: SubqueryWhere, SQL: "SELECT 1 FROM u", Depth: 1},
+			},
+		},
+		{
+			sql: "SELECT (SELECT MAX(v) FROM s) AS m FROM t GROUP BY a HAVING COUNT(*) > (SELECT 1)",
+			subs: []SubQueryInfo{
+				{Type: SubquerySelect, SQL: "SELECT MAX(v) FROM s", Depth: 1},
+				{Type: SubqueryHaving, SQL: "SELECT 1", Depth: 1},
+			},
+		},
+		{
+			sql: "SELECT * FROM t WHERE a > ALL (SELECT b FROM (SELECT b FROM s WHERE c IN (SELECT c FROM u)) x)",
+			subs: []SubQueryInfo{
+				{Type: SubqueryWhere, SQL: "SELECT b FROM (SELECT b FROM s WHERE c IN (SELECT c FROM u)) x", Depth: 1},
+				{Type: SubqueryFrom, Alias: "x", SQL: "SELECT b FROM s WHERE c IN (SELECT c FROM u)", Depth: 2},
+				{Type: SubqueryWhere, SQL: "SELECT c FROM u", Depth: 3},
+			},
+		},
+		{
+			sql: "SELECT * FROM t JOIN s ON s.id = (SELECT MIN(id) FROM u) ORDER BY (SELECT 1)",
+			subs: []SubQueryInfo{
+				{Type: SubqueryOther, SQL: "SELECT MIN(id) FROM u", Depth: 1},
+				{Type: SubqueryOther, SQL: "SELECT 1", Depth: 1},
+			},
+		},
+		{
+			sql: "UPDATE t SET a = 1 WHERE id IN (SELECT id FROM s)",
+			subs: []SubQueryInfo{
+				{Type: SubqueryWhere, SQL: "SELECT id FROM s", Depth: 1},
+			},
+		},
+		{
+			sql:  "SELECT * FROM t",
+			subs: []SubQueryInfo{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			got := []SubQueryInfo{}
+			for _, sub := range result.SubQueries {
+				sub.OuterRefs, sub.Correlation = nil, 0
+				got = append(got, sub)
+			}
+			if !reflect.DeepEqual(got, c.subs) {
+				t.Errorf("SubQueries = %+v\nwant %+v", got, c.subs)
+			}
+		})
+	}
+}
+
+func TestSubQueryCorrelation(t *testing.T) {
+	cases := []struct {
+		sql         string
+		outerRefs   [][]string // 按 SubQueries 的顺序
+		correlation []int
+	}{
+		{
+			sql:         "SELECT * FROM t WHERE id IN (SELECT tid FROM s)",
+			outerRefs:   [][]string{nil},
+			correlation: []int{0},
+		},
+		{
+			sql:         "SELECT * FROM orders o WHERE EXISTS (SELECT 1 FROM items i WHERE i.oid = o.id)",
+			outerRefs:   [][]string{{"o.id"}},
+			correlation: []int{1},
+		},
+		{
+			sql:         "SELECT * FROM orders WHERE EXISTS (SELECT 1 FROM items WHERE items.oid = orders.id)",
+			outerRefs:   [][]string{{"orders.id"}},
+			correlation: []int{1},
+		},
+		{
+			sql:         "SELECT (SELECT MAX(v) FROM s WHERE s.tid = t.id) AS m FROM t",
+			outerRefs:   [][]string{{"t.id"}},
+			correlation: []int{1},
+		},
+		{
+			// 不带限定符的列按内层的表解析
+			sql:         "SELECT * FROM t WHERE a IN (SELECT b FROM s WHERE s.k = k)",
+			outerRefs:   [][]string{nil},
+			correlation: []int{0},
+		},
+		{
+			sql: "SELECT * FROM orders o WHERE EXISTS (SELECT 1 FROM items i WHERE i.oid = o.id AND EXISTS (SELECT 1 FROM logs l WHERE l.iid = i.id AND l.uid = o.uid))",
+			outerRefs: [][]string{
+				{"o.id", "o.uid"},
+				{"i.id", "o.uid"},
+			},
+			correlation: []int{1, 2},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			if len(result.SubQueries) != len(c.outerRefs) {
+				t.Fatalf("len(SubQueries) = %d, want %d", len(result.SubQueries), len(c.outerRefs))
+			}
+			for i, sub := range result.SubQueries {
+				if len(sub.OuterRefs) != 0 || len(c.outerRefs[i]) != 0 {
+					if !reflect.DeepEqual(sub.OuterRefs, c.outerRefs[i]) {
+						t.Errorf("SubQueries[%d].OuterRefs = %q, want %q", i, sub.OuterRefs, c.outerRefs[i])
+					}
+				}
+				if sub.Correlation != c.correlation[i] {
+					t.Errorf("SubQueries[%d].Correlation = %d, want %d", i, sub.Correlation, c.correlation[i])
+				}
+			}
+		})
+	}
+}
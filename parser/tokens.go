@@ -0,0 +1,385 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// 词法单元与改写 API。
+//
+// 稳定性约定：Token、Clause、Rewriter 的字段和方法签名保持向后兼容；
+// Token.Kind 取自 MySqlLexer 的符号名，Token.Type 是生成代码中的常量值，
+// 两者都会随语法文件重新生成而变化，调用方应当优先比较 Kind 而不是 Type；
+// 子句的划分依据解析树，语法升级后边界可能更精确，但不会改变已有 Clause 名称。
+
+// 子句名称
+const (
+	ClauseSelect  = "SELECT"   // SELECT 列表
+	ClauseFrom    = "FROM"     // FROM 及表来源
+	ClauseWhere   = "WHERE"    // WHERE 条件
+	ClauseGroupBy = "GROUP BY" // GROUP BY
+	ClauseHaving  = "HAVING"   // HAVING
+	ClauseOrderBy = "ORDER BY" // ORDER BY
+	ClauseLimit   = "LIMIT"    // LIMIT
+)
+
+// Token 一个词法单元
+type Token struct {
+	Index  int    // 在 TokenList.Tokens 中的下标
+	Kind   string // 词法符号名，如 SELECT、ID、STRING_LITERAL、SPACE
+	Type   int    // 词法类型，对应 MySqlLexer 中的常量
+	Text   string
+	Start  int  // 在原始 SQL 中的起始字节偏移
+	Stop   int  // 在原始 SQL 中的结束字节偏移（不含）
+	Line   int  // 行号，从 1 开始
+	Column int  // 列号（字符），从 0 开始
+	Hidden bool // 是否位于隐藏通道（空白、注释）
+}
+
+// Clause 一个子句覆盖的词法单元范围
+type Clause struct {
+	Name  string // 子句名称，见 Clause* 常量
+	Start int    // 起始词法单元下标
+	Stop  int    // 结束词法单元下标（含）
+	Depth int    // 所在查询的嵌套深度，最外层为 0
+}
+
+// TokenList 一条 SQL 的全部词法单元（含隐藏通道）及子句边界
+type TokenList struct {
+	SQL        string
+	Tokens     []Token // 不含 EOF
+	Clauses    []Clause
+	Directives []Directive // 注释中的忽略指令
+}
+
+// ParseTokens 对 sql 做词法和语法分析，返回词法单元及子句边界，语法错误时返回 error。
+// sql 须为 UTF-8，其他编码先用 Decode 转换，含有非法字节时返回 *InvalidEncodingError
+func ParseTokens(sql string) (*TokenList, error) {
+	list, _, err := parseTokens(sql)
+	return list, err
+}
+
+// parseTokens 同 ParseTokens，同时返回语法树
+func parseTokens(sql string) (*TokenList, antlr.ParseTree, error) {
+	if _, err := decodeSQL(sql, EncodingUTF8); err != nil {
+		return nil, nil, err
+	}
+	lexer := NewMySqlLexer(antlr.NewInputStream(lexInput(sql)))
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := NewMySqlParser(stream)
+
+	errs := &syntaxErrorListener{}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errs)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errs)
+
+	clauses := &clauseListener{}
+	tree := parseRoot(p)
+	antlr.ParseTreeWalkerDefault.Walk(clauses, tree)
+	if len(errs.errors) > 0 {
+		return nil, nil, errs.errors[0]
+	}
+
+	offsets := byteOffsets(sql)
+	list := &TokenList{SQL: sql, Clauses: clauses.clauses}
+	for _, t := range stream.GetAllTokens() {
+		if t.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+		kind := ""
+		if t.GetTokenType() < len(lexer.SymbolicNames) {
+			kind = lexer.SymbolicNames[t.GetTokenType()]
+		}
+		start, stop := offsets[t.GetStart()], offsets[t.GetStop()+1]
+		if d, ok := parseDirective(t.GetTokenType(), sql[start:stop]); ok {
+			d.Line, d.Start, d.Stop = t.GetLine(), start, stop
+			list.Directives = append(list.Directives, d)
+		}
+		list.Tokens = append(list.Tokens, Token{
+			Index:  len(list.Tokens),
+			Kind:   kind,
+			Type:   t.GetTokenType(),
+			Text:   sql[start:stop],
+			Start:  start,
+			Stop:   stop,
+			Line:   t.GetLine(),
+			Column: t.GetColumn(),
+			Hidden: t.GetChannel() != antlr.TokenDefaultChannel,
+		})
+	}
+	sort.SliceStable(list.Clauses, func(i, j int) bool { return list.Clauses[i].Start < list.Clauses[j].Start })
+	return list, tree, nil
+}
+
+// Clause 返回指定名称的最外层子句，同一层有多个时取第一个
+func (l *TokenList) Clause(name string) (Clause, bool) {
+	found := false
+	var clause Clause
+	for _, c := range l.Clauses {
+		if c.Name == name && (!found || c.Depth < clause.Depth) {
+			clause, found = c, true
+		}
+	}
+	return clause, found
+}
+
+// Rewriter 基于 TokenList 的改写器，未改动的部分按原始字节输出
+type Rewriter struct {
+	list     *TokenList
+	before   map[int][]string
+	after    map[int][]string
+	replaces []replaceOp
+}
+
+type replaceOp struct {
+	from, to int
+	text     string
+}
+
+// NewRewriter 创建一个改写器
+func NewRewriter(list *TokenList) *Rewriter {
+	return &Rewriter{
+		list:   list,
+		before: map[int][]string{},
+		after:  map[int][]string{},
+	}
+}
+
+// InsertBefore 在第 i 个词法单元之前插入文本，同一位置多次插入按调用顺序输出。
+// 插入位置位于已有替换的内部时返回错误，替换范围第一个词法单元之前可以插入
+func (r *Rewriter) InsertBefore(i int, text string) error {
+	if err := r.check(i, i); err != nil {
+		return err
+	}
+	for _, op := range r.replaces {
+		if op.from < i && i <= op.to {
+			return fmt.Errorf("insert before %d is inside replace range [%d, %d]", i, op.from, op.to)
+		}
+	}
+	r.before[i] = append(r.before[i], text)
+	return nil
+}
+
+// InsertAfter 在第 i 个词法单元之后插入文本，同一位置多次插入按调用顺序输出。
+// 插入位置位于已有替换的内部时返回错误，替换范围最后一个词法单元之后可以插入
+func (r *Rewriter) InsertAfter(i int, text string) error {
+	if err := r.check(i, i); err != nil {
+		return err
+	}
+	for _, op := range r.replaces {
+		if op.from <= i && i < op.to {
+			return fmt.Errorf("insert after %d is inside replace range [%d, %d]", i, op.from, op.to)
+		}
+	}
+	r.after[i] = append(r.after[i], text)
+	return nil
+}
+
+// Replace 把 [from, to] 范围的词法单元替换为 text，范围不能与已有的替换重叠，
+// 也不能覆盖已有的插入位置（from 之前和 to 之后除外），否则返回错误
+func (r *Rewriter) Replace(from, to int, text string) error {
+	if err := r.check(from, to); err != nil {
+		return err
+	}
+	for _, op := range r.replaces {
+		if from <= op.to && op.from <= to {
+			return fmt.Errorf("replace range [%d, %d] overlaps [%d, %d]", from, to, op.from, op.to)
+		}
+	}
+	for i := from; i <= to; i++ {
+		if i > from && len(r.before[i]) > 0 {
+			return fmt.Errorf("replace range [%d, %d] covers insert before %d", from, to, i)
+		}
+		if i < to && len(r.after[i]) > 0 {
+			return fmt.Errorf("replace range [%d, %d] covers insert after %d", from, to, i)
+		}
+	}
+	r.replaces = append(r.replaces, replaceOp{from: from, to: to, text: text})
+	return nil
+}
+
+// Delete 删除 [from, to] 范围的词法单元
+func (r *Rewriter) Delete(from, to int) error {
+	return r.Replace(from, to, "")
+}
+
+// String 输出改写后的 SQL
+func (r *Rewriter) String() string {
+	var b strings.Builder
+	sql := r.list.SQL
+	pos := 0
+	for i := 0; i < len(r.list.Tokens); i++ {
+		t := r.list.Tokens[i]
+		// 词法单元之间被词法器跳过的内容原样保留
+		b.WriteString(sql[pos:t.Start])
+		for _, s := range r.before[i] {
+			b.WriteString(s)
+		}
+		if op, ok := r.replaceAt(i); ok {
+			b.WriteString(op.text)
+			for _, s := range r.after[op.to] {
+				b.WriteString(s)
+			}
+			i = op.to
+			pos = r.list.Tokens[op.to].Stop
+			continue
+		}
+		b.WriteString(t.Text)
+		for _, s := range r.after[i] {
+			b.WriteString(s)
+		}
+		pos = t.Stop
+	}
+	b.WriteString(sql[pos:])
+	return b.String()
+}
+
+func (r *Rewriter) replaceAt(i int) (replaceOp, bool) {
+	for _, op := range r.replaces {
+		if op.from == i {
+			return op, true
+		}
+	}
+	return replaceOp{}, false
+}
+
+func (r *Rewriter) check(from, to int) error {
+	if from < 0 || to >= len(r.list.Tokens) || from > to {
+		return fmt.Errorf("token range [%d, %d] out of bounds [0, %d)", from, to, len(r.list.Tokens))
+	}
+	return nil
+}
+
+// byteOffsets 返回字符下标到字节偏移的映射，多出的最后一项为字符串长度
+func byteOffsets(s string) []int {
+	offsets := make([]int, 0, len(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(s))
+}
+
+// clauseListener 记录每个查询中各子句的词法单元范围
+type clauseListener struct {
+	*BaseMySqlParserListener
+	depth   int
+	window  int
+	clauses []Clause
+}
+
+func (l *clauseListener) add(name string, start, stop antlr.Token) {
+	if start == nil || stop == nil || l.window > 0 {
+		return
+	}
+	// UNION 整体的 ORDER BY/LIMIT 位于各个查询之外，归入外层
+	depth := l.depth - 1
+	if depth < 0 {
+		depth = 0
+	}
+	l.clauses = append(l.clauses, Clause{Name: name, Start: start.GetTokenIndex(), Stop: stop.GetTokenIndex(), Depth: depth})
+}
+
+func (l *clauseListener) EnterQuerySpecification(ctx *QuerySpecificationContext) { l.depth++ }
+func (l *clauseListener) ExitQuerySpecification(ctx *QuerySpecificationContext)  { l.depth-- }
+func (l *clauseListener) EnterQuerySpecificationNointo(ctx *QuerySpecificationNointoContext) {
+	l.depth++
+}
+func (l *clauseListener) ExitQuerySpecificationNointo(ctx *QuerySpecificationNointoContext) {
+	l.depth--
+}
+
+// CTE 中的查询嵌套在主语句之内
+func (l *clauseListener) EnterDmlStatement(ctx *DmlStatementContext) {
+	if _, ok := ctx.GetParent().(*CommonTableExpressionsContext); ok {
+		l.depth++
+	}
+}
+
+func (l *clauseListener) ExitDmlStatement(ctx *DmlStatementContext) {
+	if _, ok := ctx.GetParent().(*CommonTableExpressionsContext); ok {
+		l.depth--
+	}
+}
+
+// 窗口函数 OVER (... ORDER BY ...) 中的子句不是查询子句
+func (l *clauseListener) EnterOverClause(ctx *OverClauseContext) { l.window++ }
+func (l *clauseListener) ExitOverClause(ctx *OverClauseContext)  { l.window-- }
+
+func (l *clauseListener) EnterSelectElements(ctx *SelectElementsContext) {
+	l.add(ClauseSelect, ctx.GetStart(), ctx.GetStop())
+}
+
+func (l *clauseListener) EnterFromClause(ctx *FromClauseContext) {
+	if ctx.FROM() != nil && ctx.TableSources() != nil {
+		l.add(ClauseFrom, ctx.FROM().GetSymbol(), ctx.TableSources().GetStop())
+	}
+	if ctx.WHERE() != nil && ctx.GetWhereExpr() != nil {
+		l.add(ClauseWhere, ctx.WHERE().GetSymbol(), ctx.GetWhereExpr().GetStop())
+	}
+}
+
+func (l *clauseListener) EnterGroupByClause(ctx *GroupByClauseContext) {
+	l.add(ClauseGroupBy, ctx.GetStart(), ctx.GetStop())
+}
+
+func (l *clauseListener) EnterHavingClause(ctx *HavingClauseContext) {
+	l.add(ClauseHaving, ctx.GetStart(), ctx.GetStop())
+}
+
+func (l *clauseListener) EnterOrderByClause(ctx *OrderByClauseContext) {
+	l.add(ClauseOrderBy, ctx.GetStart(), ctx.GetStop())
+}
+
+func (l *clauseListener) EnterLimitClause(ctx *LimitClauseContext) {
+	l.add(ClauseLimit, ctx.GetStart(), ctx.GetStop())
+}
+
+// ParseError 一个词法或语法错误，各解析函数返回的语法错误都是 *ParseError
+type ParseError struct {
+	Line   int    `json:"line"`   // 行号，从 1 开始
+	Column int    `json:"column"` // 列号（字符），从 0 开始
+	Token  string `json:"token"`  // 出错的词法单元原文，词法错误时为空，到达末尾时为 <EOF>
+	Msg    string `json:"msg"`
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d:%d %s", e.Line, e.Column, e.Msg)
+}
+
+// syntaxErrorListener 收集词法和语法错误
+type syntaxErrorListener struct {
+	*antlr.DefaultErrorListener
+	errors []*ParseError
+	bail   bool // 记录第一个错误后停止分析，见 parseTree
+}
+
+// bailout syntaxErrorListener 停止分析时 panic 的值
+type bailout struct{}
+
+func (l *syntaxErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
+	err := &ParseError{Line: line, Column: column, Msg: msg}
+	if t, ok := offendingSymbol.(antlr.Token); ok {
+		err.Token = t.GetText()
+	}
+	l.errors = append(l.errors, err)
+	if l.bail {
+		panic(bailout{})
+	}
+}
+
+// parseTree 调用 parseRoot。errs.bail 时在第一个错误处停止，不再做错误恢复，此时返回 nil
+func parseTree(p *MySqlParser, errs *syntaxErrorListener) (tree antlr.ParseTree) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok || !errs.bail {
+				panic(r)
+			}
+			tree = nil
+		}
+	}()
+	return parseRoot(p)
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// rewriteSQL 含注释、制表符、CRLF 和多字节字符，检查未改动的部分按原始字节输出
+const rewriteSQL = "SELECT  /* 注释 */ a,\tb\r\nFROM `表`  -- 尾注\nWHERE c = '中文' ;"
+
+// tokenIndex 返回第一个文本为 text 的词法单元下标
+func tokenIndex(t *testing.T, list *TokenList, text string) int {
+	t.Helper()
+	for _, tok := range list.Tokens {
+		if tok.Text == text {
+			return tok.Index
+		}
+	}
+	t.Fatalf("no token %q", text)
+	return -1
+}
+
+func TestParseTokensOffsets(t *testing.T) {
+	list, err := ParseTokens(rewriteSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for i, tok := range list.Tokens {
+		if tok.Index != i || rewriteSQL[tok.Start:tok.Stop] != tok.Text {
+			t.Errorf("Tokens[%d] = %+v", i, tok)
+		}
+		b.WriteString(tok.Text)
+	}
+	if b.String() != rewriteSQL {
+		t.Errorf("tokens = %q, want %q", b.String(), rewriteSQL)
+	}
+	if got := NewRewriter(list).String(); got != rewriteSQL {
+		t.Errorf("unchanged String() = %q, want %q", got, rewriteSQL)
+	}
+}
+
+func TestRewriter(t *testing.T) {
+	list, err := ParseTokens(rewriteSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, table := tokenIndex(t, list, "a"), tokenIndex(t, list, "b"), tokenIndex(t, list, "`表`")
+	cases := []struct {
+		name string
+		edit func(r *Rewriter) error
+		want string
+	}{
+		{
+			name: "replace",
+			edit: func(r *Rewriter) error { return r.Replace(table, table, "`t2`") },
+			want: "SELECT  /* 注释 */ a,\tb\r\nFROM `t2`  -- 尾注\nWHERE c = '中文' ;",
+		},
+		{
+			name: "replace range",
+			edit: func(r *Rewriter) error { return r.Replace(a, b, "*") },
+			want: "SELECT  /* 注释 */ *\r\nFROM `表`  -- 尾注\nWHERE c = '中文' ;",
+		},
+		{
+			name: "delete",
+			edit: func(r *Rewriter) error { return r.Delete(a, a+1) },
+			want: "SELECT  /* 注释 */ \tb\r\nFROM `表`  -- 尾注\nWHERE c = '中文' ;",
+		},
+		{
+			name: "insert",
+			edit: func(r *Rewriter) error {
+				if err := r.InsertBefore(a, "x, "); err != nil {
+					return err
+				}
+				if err := r.InsertBefore(a, "y, "); err != nil {
+					return err
+				}
+				return r.InsertAfter(table, " AS s")
+			},
+			want: "SELECT  /* 注释 */ x, y, a,\tb\r\nFROM `表` AS s  -- 尾注\nWHERE c = '中文' ;",
+		},
+		{
+			name: "insert around replace",
+			edit: func(r *Rewriter) error {
+				if err := r.Replace(a, b, "*"); err != nil {
+					return err
+				}
+				if err := r.InsertBefore(a, "DISTINCT "); err != nil {
+					return err
+				}
+				return r.InsertAfter(b, " ")
+			},
+			want: "SELECT  /* 注释 */ DISTINCT * \r\nFROM `表`  -- 尾注\nWHERE c = '中文' ;",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewRewriter(list)
+			if err := c.edit(r); err != nil {
+				t.Fatal(err)
+			}
+			if got := r.String(); got != c.want {
+				t.Errorf("String() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRewriterErrors(t *testing.T) {
+	list, err := ParseTokens("SELECT a, b FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := tokenIndex(t, list, "a"), tokenIndex(t, list, "b")
+	cases := []struct {
+		name string
+		edit func(r *Rewriter) error
+	}{
+		{"out of bounds", func(r *Rewriter) error { return r.Replace(0, len(list.Tokens), "") }},
+		{"negative", func(r *Rewriter) error { return r.InsertBefore(-1, "x") }},
+		{"reversed", func(r *Rewriter) error { return r.Delete(b, a) }},
+		{"overlap", func(r *Rewriter) error {
+			_ = r.Replace(a, b, "*")
+			return r.Replace(b, b+2, "x")
+		}},
+		{"insert before inside replace", func(r *Rewriter) error {
+			_ = r.Replace(a, b, "*")
+			return r.InsertBefore(b, "x")
+		}},
+		{"insert after inside replace", func(r *Rewriter) error {
+			_ = r.Replace(a, b, "*")
+			return r.InsertAfter(a, "x")
+		}},
+		{"replace covers insert before", func(r *Rewriter) error {
+			_ = r.InsertBefore(b, "x")
+			return r.Replace(a, b, "*")
+		}},
+		{"replace covers insert after", func(r *Rewriter) error {
+			_ = r.InsertAfter(a, "x")
+			return r.Replace(a, b, "*")
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewRewriter(list)
+			if err := c.edit(r); err == nil {
+				t.Errorf("got no error, String() = %q", r.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package parser
+
+import "github.com/antlr/antlr4/runtime/Go/antlr"
+
+// 集合操作类型。当前语法不支持 INTERSECT 和 EXCEPT
+const (
+	UnionDistinct = "UNION" // UNION、UNION DISTINCT
+	UnionAll      = "UNION ALL"
+)
+
+// UnionInfo UNION 的一个分支
+type UnionInfo struct {
+	Type    string `json:"type"`    // 见 Union* 常量
+	Content string `json:"content"` // UNION 之后的查询原文，不含作用于整个 UNION 的 ORDER BY、LIMIT
+}
+
+func (l *sqlListener) EnterUnionSelect(ctx *UnionSelectContext) {
+	var branches []antlr.ParserRuleContext
+	var all []bool
+	for _, stmt := range ctx.AllUnionStatement() {
+		s := stmt.(*UnionStatementContext)
+		var branch antlr.ParserRuleContext = s.QuerySpecificationNointo()
+		if s.QueryExpressionNointo() != nil {
+			branch = s.QueryExpressionNointo()
+		}
+		branches, all = append(branches, branch), append(all, s.ALL() != nil)
+	}
+	if ctx.UNION() != nil {
+		var branch antlr.ParserRuleContext = ctx.QuerySpecification()
+		if ctx.QueryExpression() != nil {
+			branch = ctx.QueryExpression()
+		}
+		branches, all = append(branches, branch), append(all, ctx.ALL() != nil)
+	}
+	l.addUnions(branches, all, ctx.OrderByClause(), ctx.LimitClause())
+}
+
+func (l *sqlListener) EnterUnionParenthesisSelect(ctx *UnionParenthesisSelectContext) {
+	var branches []antlr.ParserRuleContext
+	var all []bool
+	for _, p := range ctx.AllUnionParenthesis() {
+		u := p.(*UnionParenthesisContext)
+		branches, all = append(branches, u.QueryExpressionNointo()), append(all, u.ALL() != nil)
+	}
+	if ctx.UNION() != nil {
+		branches, all = append(branches, ctx.QueryExpression()), append(all, ctx.ALL() != nil)
+	}
+	l.addUnions(branches, all, ctx.OrderByClause(), ctx.LimitClause())
+}
+
+// addUnions 记录 UNION 的各个分支。没有括号的最后一个分支末尾的 ORDER BY、LIMIT
+// 作用于整个 UNION，从分支原文中去掉；最外层 UNION 的这两个子句记录在 UnionOrderBy、UnionLimit
+func (l *sqlListener) addUnions(branches []antlr.ParserRuleContext, all []bool, orderBy IOrderByClauseContext, limit ILimitClauseContext) {
+	for i, branch := range branches {
+		u := UnionInfo{Type: UnionDistinct, Content: originalText(branch)}
+		if all[i] {
+			u.Type = UnionAll
+		}
+		if i == len(branches)-1 {
+			var content string
+			if content, orderBy, limit = unionTail(branch, orderBy, limit); content != "" {
+				u.Content = content
+			}
+		}
+		l.result.Unions = append(l.result.Unions, u)
+	}
+	if l.depth > 0 || l.union {
+		return
+	}
+	l.union = true
+	if orderBy != nil {
+		l.result.UnionOrderBy = originalText(orderBy)
+	}
+	if limit != nil {
+		l.result.UnionLimit = originalText(limit)
+	}
+}
+
+// unionTail 查找最后一个分支末尾的 ORDER BY、LIMIT，返回去掉它们后的分支原文；
+// 没有时返回空串，orderBy、limit 保持不变
+func unionTail(branch antlr.ParserRuleContext, orderBy IOrderByClauseContext, limit ILimitClauseContext) (string, IOrderByClauseContext, ILimitClauseContext) {
+	children := branch.GetChildren()
+	end := len(children)
+	for end > 1 {
+		switch c := children[end-1].(type) {
+		case IOrderByClauseContext:
+			orderBy = c
+		case ILimitClauseContext:
+			limit = c
+		case antlr.ParserRuleContext:
+			if end == len(children) {
+				return "", orderBy, limit
+			}
+			return tokenText(branch.GetStart(), c.GetStop()), orderBy, limit
+		default:
+			return "", orderBy, limit
+		}
+		end--
+	}
+	return "", orderBy, limit
+}
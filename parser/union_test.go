@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	cases := []struct {
+		sql     string
+		unions  []UnionInfo
+		orderBy string
+		limit   string
+	}{
+		{
+			sql:    "SELECT a FROM x UNION SELECT b FROM y",
+			unions: []UnionInfo{{Type: UnionDistinct, Content: "SELECT b FROM y"}},
+		},
+		{
+			sql: "SELECT a FROM x UNION ALL SELECT b FROM y UNION ALL SELECT c FROM z",
+			unions: []UnionInfo{
+				{Type: UnionAll, Content: "SELECT b FROM y"},
+				{Type: UnionAll, Content: "SELECT c FROM z"},
+			},
+		},
+		{
+			sql: "SELECT a FROM x UNION SELECT b FROM y UNION ALL SELECT c FROM z UNION DISTINCT SELECT d FROM w",
+			unions: []UnionInfo{
+				{Type: UnionDistinct, Content: "SELECT b FROM y"},
+				{Type: UnionAll, Content: "SELECT c FROM z"},
+				{Type: UnionDistinct, Content: "SELECT d FROM w"},
+			},
+		},
+		{
+			sql: "(SELECT a FROM x) UNION ALL (SELECT b FROM y) UNION (SELECT c FROM z)",
+			unions: []UnionInfo{
+				{Type: UnionAll, Content: "(SELECT b FROM y)"},
+				{Type: UnionDistinct, Content: "(SELECT c FROM z)"},
+			},
+		},
+		{
+			sql:     "SELECT a FROM x UNION DISTINCT SELECT b FROM y ORDER BY a DESC LIMIT 10",
+			unions:  []UnionInfo{{Type: UnionDistinct, Content: "SELECT b FROM y"}},
+			orderBy: "ORDER BY a DESC",
+			limit:   "LIMIT 10",
+		},
+		{
+			sql:    "SELECT a FROM x UNION ALL SELECT b FROM y WHERE c = 1 GROUP BY b LIMIT 5",
+			unions: []UnionInfo{{Type: UnionAll, Content: "SELECT b FROM y WHERE c = 1 GROUP BY b"}},
+			limit:  "LIMIT 5",
+		},
+		{
+			sql:     "(SELECT a FROM x) UNION (SELECT b FROM y) ORDER BY a LIMIT 1",
+			unions:  []UnionInfo{{Type: UnionDistinct, Content: "(SELECT b FROM y)"}},
+			orderBy: "ORDER BY a",
+			limit:   "LIMIT 1",
+		},
+		{
+			sql:     "SELECT a FROM x UNION (SELECT b FROM y ORDER BY b LIMIT 2) ORDER BY a",
+			unions:  []UnionInfo{{Type: UnionDistinct, Content: "(SELECT b FROM y ORDER BY b LIMIT 2)"}},
+			orderBy: "ORDER BY a",
+		},
+		{
+			// 子查询中的 UNION 只去掉分支中的 LIMIT，不计入 UnionLimit
+			sql: "SELECT * FROM (SELECT a FROM x UNION SELECT b FROM y LIMIT 3) d UNION SELECT 1 LIMIT 4",
+			unions: []UnionInfo{
+				{Type: UnionDistinct, Content: "SELECT 1"},
+				{Type: UnionDistinct, Content: "SELECT b FROM y"},
+			},
+			limit: "LIMIT 4",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			if !reflect.DeepEqual(result.Unions, c.unions) {
+				t.Errorf("Unions = %+v, want %+v", result.Unions, c.unions)
+			}
+			if result.UnionOrderBy != c.orderBy || result.UnionLimit != c.limit {
+				t.Errorf("UnionOrderBy, UnionLimit = %q, %q, want %q, %q", result.UnionOrderBy, result.UnionLimit, c.orderBy, c.limit)
+			}
+		})
+	}
+}
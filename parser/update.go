@@ -0,0 +1,72 @@
+package parser
+
+// UpdateInfo UPDATE 语句
+type UpdateInfo struct {
+	Tables      []string     `json:"tables"`      // 被更新的表（多表 UPDATE 时为 UPDATE 后的全部表），去掉反引号，默认小写（见 ParseOptions）
+	Assignments []Assignment `json:"assignments"` // SET 中的赋值，按出现顺序
+	Where       string       `json:"where"`       // WHERE 条件原文，拆分后的条件见 SqlParseResult.Where
+	Limit       *LimitInfo   `json:"limit"`       // 没有 LIMIT 时为 nil，多表 UPDATE 不支持 LIMIT
+}
+
+// Assignment SET 中的一个赋值
+type Assignment struct {
+	Table  string `json:"table"`  // 列所属的表，限定符按别名解析；多表 UPDATE 中不带限定的列为空
+	Column string `json:"column"` // 列名
+	Expr   string `json:"expr"`   // 赋值表达式原文，SET a = DEFAULT 时为 DEFAULT
+}
+
+// UPDATE 语句与最外层查询同级，其中的子查询深度从 2 开始
+func (l *sqlListener) EnterSingleUpdateStatement(ctx *SingleUpdateStatementContext) {
+	l.depth++
+	l.addUpdate([]tableSource{singleSource(ctx.TableName(), ctx.Uid(), l.fold)}, ctx.AllUpdatedElement(), ctx.Expression())
+	if ctx.LimitClause() != nil {
+		l.result.Update.Limit = limitInfo(ctx.LimitClause().(*LimitClauseContext))
+	}
+}
+
+func (l *sqlListener) ExitSingleUpdateStatement(ctx *SingleUpdateStatementContext) { l.depth-- }
+
+func (l *sqlListener) EnterMultipleUpdateStatement(ctx *MultipleUpdateStatementContext) {
+	l.depth++
+	l.addUpdate(collectSources(ctx.TableSources(), nil, l.fold), ctx.AllUpdatedElement(), ctx.Expression())
+}
+
+func (l *sqlListener) ExitMultipleUpdateStatement(ctx *MultipleUpdateStatementContext) { l.depth-- }
+
+// singleSource 单表语句中带可选别名的表，表名和别名经过 fold
+func singleSource(name ITableNameContext, alias IUidContext, fold func(string) string) tableSource {
+	table := fold(unquoteName(name.GetText()))
+	s := tableSource{name: fold(uidText(alias)), table: table, tables: []string{table}}
+	if s.name == "" {
+		s.name = table
+	}
+	return s
+}
+
+func (l *sqlListener) addUpdate(sources []tableSource, elements []IUpdatedElementContext, where IExpressionContext) {
+	update := &UpdateInfo{Tables: allTables(sources), Assignments: []Assignment{}}
+	for _, element := range elements {
+		e := element.(*UpdatedElementContext)
+		a := Assignment{Expr: "DEFAULT"}
+		if e.Expression() != nil {
+			a.Expr = originalText(e.Expression())
+		}
+		var qualifier string
+		qualifier, a.Column = splitColumnName(e.FullColumnName().GetText())
+		switch {
+		case qualifier == "" && len(sources) == 1:
+			a.Table = sources[0].table
+		case qualifier != "":
+			a.Table = l.fold(qualifier)
+			if s, ok := resolveTable(qualifier, sources, l.fold); ok && s.table != "" {
+				a.Table = s.table
+			}
+		}
+		update.Assignments = append(update.Assignments, a)
+	}
+	if where != nil {
+		update.Where = originalText(where)
+		l.setWhere(where)
+	}
+	l.result.Update = update
+}
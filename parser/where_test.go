@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWhere(t *testing.T) {
+	cases := []struct {
+		sql   string
+		expr  string
+		where []string
+	}{
+		{
+			sql:   "SELECT * FROM t WHERE a = 1 AND (b = 2 OR c = 3) && d IS NULL",
+			expr:  "a = 1 AND (b = 2 OR c = 3) && d IS NULL",
+			where: []string{"a = 1", "(b = 2 OR c = 3)", "d IS NULL"},
+		},
+		{
+			sql:   "SELECT * FROM t WHERE a = 1 OR b = 2 AND c = 3",
+			expr:  "a = 1 OR b = 2 AND c = 3",
+			where: []string{"a = 1 OR b = 2 AND c = 3"},
+		},
+		{
+			sql:   "SELECT * FROM t WHERE a = 1 AND b = 2 OR c = 3 AND d = 4",
+			expr:  "a = 1 AND b = 2 OR c = 3 AND d = 4",
+			where: []string{"a = 1 AND b = 2 OR c = 3 AND d = 4"},
+		},
+		{
+			sql:   "SELECT * FROM t WHERE a = 1 AND b = 2 XOR c = 3",
+			expr:  "a = 1 AND b = 2 XOR c = 3",
+			where: []string{"a = 1 AND b = 2 XOR c = 3"},
+		},
+		{
+			sql:   "SELECT * FROM t WHERE a = 1 AND (b = 2 OR c = 3) AND d = 4 && e = 5",
+			expr:  "a = 1 AND (b = 2 OR c = 3) AND d = 4 && e = 5",
+			where: []string{"a = 1", "(b = 2 OR c = 3)", "d = 4", "e = 5"},
+		},
+		{
+			sql:   "SELECT * FROM t WHERE id IN (SELECT id FROM s WHERE x = 1 AND y = 2)",
+			expr:  "id IN (SELECT id FROM s WHERE x = 1 AND y = 2)",
+			where: []string{"id IN (SELECT id FROM s WHERE x = 1 AND y = 2)"},
+		},
+		{
+			sql:   "SELECT * FROM (SELECT * FROM s WHERE x = 1) d",
+			where: []string{},
+		},
+		{
+			sql:   "UPDATE t SET a = 1 WHERE id = 2 AND v < 3",
+			expr:  "id = 2 AND v < 3",
+			where: []string{"id = 2", "v < 3"},
+		},
+		{
+			sql:   "DELETE FROM t WHERE created < NOW()",
+			expr:  "created < NOW()",
+			where: []string{"created < NOW()"},
+		},
+		{
+			sql:   "SELECT * FROM a WHERE x = 1 UNION SELECT * FROM b WHERE y = 2",
+			expr:  "x = 1",
+			where: []string{"x = 1"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.sql, func(t *testing.T) {
+			result := mustParse(t, c.sql)
+			if result.WhereExpr != c.expr {
+				t.Errorf("WhereExpr = %q, want %q", result.WhereExpr, c.expr)
+			}
+			if !reflect.DeepEqual(result.Where, c.where) {
+				t.Errorf("Where = %q, want %q", result.Where, c.where)
+			}
+			if len(result.Predicates) != len(result.Where) {
+				t.Errorf("%d predicates for %d conditions", len(result.Predicates), len(result.Where))
+			}
+		})
+	}
+}
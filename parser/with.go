@@ -0,0 +1,160 @@
+package parser
+
+import "github.com/antlr/antlr4/runtime/Go/antlr"
+
+// WithInfo WITH 子句中的一个公用表表达式（CTE）。
+// 识别 WITH ... SELECT/UPDATE/DELETE 和 CREATE VIEW ... AS WITH ... 中的 WITH，
+// 子查询、INSERT ... SELECT 中的 WITH 语法不支持，会报语法错误
+type WithInfo struct {
+	Name      string   `json:"name"`      // CTE 名称
+	Columns   []string `json:"columns"`   // 名称后的列名列表，没有时为空
+	Content   string   `json:"content"`   // CTE 的查询原文，不含外层括号
+	Recursive bool     `json:"recursive"` // 是否位于 WITH RECURSIVE 中
+}
+
+func (l *sqlListener) EnterWithClause(ctx *WithClauseContext) {
+	l.recursive = ctx.RECURSIVE() != nil
+}
+
+// 多个 CTE 在语法树中逐个嵌套，每进入一层记录一个
+func (l *sqlListener) EnterCommonTableExpressions(ctx *CommonTableExpressionsContext) {
+	with := WithInfo{
+		Name:      uidText(ctx.CteName().(*CteNameContext).Uid()),
+		Columns:   []string{},
+		Recursive: l.recursive,
+	}
+	for _, col := range ctx.AllCteColumnName() {
+		with.Columns = append(with.Columns, uidText(col.(*CteColumnNameContext).Uid()))
+	}
+	if ctx.DmlStatement() != nil {
+		with.Content = originalText(ctx.DmlStatement())
+	}
+	l.ctes[l.fold(with.Name)] = true
+	l.result.With = append(l.result.With, with)
+}
+
+// CTE 中的查询不是最外层查询
+func (l *sqlListener) EnterDmlStatement(ctx *DmlStatementContext) {
+	if _, ok := ctx.GetParent().(*CommonTableExpressionsContext); ok {
+		l.depth++
+	}
+}
+
+func (l *sqlListener) ExitDmlStatement(ctx *DmlStatementContext) {
+	if _, ok := ctx.GetParent().(*CommonTableExpressionsContext); ok {
+		l.depth--
+	}
+}
+
+// dropCteTables 从 Tables 中去掉对 CTE 的引用
+func (l *sqlListener) dropCteTables() {
+	tables := l.result.Tables[:0]
+	for _, name := range l.result.Tables {
+		if !l.ctes[name] {
+			tables = append(tables, name)
+		}
+	}
+	l.result.Tables = tables
+}
+
+// 生成代码中的 ATN 状态号，parseRoot 按生成的规则函数的方式调用各规则时使用。
+// 语法重新生成后这些值可能变化，TestParseRootStates 从生成代码中核对
+const (
+	stateRoot          = 0    // root 规则的起始状态
+	stateSqlStatements = 2    // sqlStatements 规则的起始状态
+	stateSqlStatement  = 4    // sqlStatement 规则的起始状态
+	stateDmlStatement  = 10   // dmlStatement 规则的起始状态
+	stateRootStmts     = 680  // root 中调用 sqlStatements
+	stateRootEOF       = 687  // root 中匹配 EOF
+	stateStmtsLast     = 703  // sqlStatements 中调用最后一条 sqlStatement
+	stateStmtsSemi     = 708  // sqlStatements 中匹配最后一条语句后的分号
+	stateStmtDml       = 715  // sqlStatement 中调用 dmlStatement
+	stateViewWith      = 1344 // createView 中调用 withClause
+	stateDmlSelect     = 765  // dmlStatement 中调用 selectStatement
+	stateDmlUpdate     = 767  // dmlStatement 中调用 updateStatement
+	stateDmlDelete     = 768  // dmlStatement 中调用 deleteStatement
+)
+
+// parseRoot 解析全部语句，返回语法树的根节点，各入口都用它代替 p.Root()。
+//
+// 生成的语法只在 CREATE VIEW 中接受 WITH，这里补上以 WITH 开头的语句：语句以 WITH 开头时
+// 依次解析 withClause 和其后的 SELECT/UPDATE/DELETE，二者放在同一个 DmlStatement 下，
+// 与 CREATE VIEW 中的形状一致，监听器先看到 CTE 再看到主语句。调用各规则前设置的状态号
+// 取自生成代码中调用该规则的位置，供错误恢复和预测计算后续的词法单元；
+// 各条语句都按 sqlStatements 中最后一条语句的位置调用，预测时其后可以是分号或 EOF
+func parseRoot(p *MySqlParser) antlr.ParseTree {
+	stream := p.GetTokenStream()
+	if stream.LA(1) != MySqlParserWITH {
+		return p.Root()
+	}
+	root := NewRootContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(root, stateRoot, MySqlParserRULE_root)
+	defer p.ExitRule()
+
+	p.SetState(stateRootStmts)
+	stmts := NewSqlStatementsContext(p, root, p.GetState())
+	p.EnterRule(stmts, stateSqlStatements, MySqlParserRULE_sqlStatements)
+	for stream.LA(1) != antlr.TokenEOF {
+		start := stream.Index()
+		switch stream.LA(1) {
+		case MySqlParserWITH:
+			p.SetState(stateStmtsLast)
+			withStatement(p)
+		case MySqlParserSEMI:
+			p.SetState(stateStmtsSemi)
+			p.Match(MySqlParserSEMI)
+		default:
+			p.SetState(stateStmtsLast)
+			p.SqlStatement()
+		}
+		if stream.Index() == start {
+			// 错误恢复没有消耗任何输入时跳过当前词法单元，避免死循环
+			t := p.GetCurrentToken()
+			p.NotifyErrorListeners("extraneous input '"+t.GetText()+"'", t, nil)
+			p.Consume()
+		}
+	}
+	p.ExitRule()
+
+	p.SetState(stateRootEOF)
+	p.Match(MySqlParserEOF)
+	return root
+}
+
+// withStatement 解析 WITH ... SELECT/UPDATE/DELETE，见 parseRoot
+func withStatement(p *MySqlParser) {
+	stmt := NewSqlStatementContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(stmt, stateSqlStatement, MySqlParserRULE_sqlStatement)
+	defer p.ExitRule()
+
+	p.SetState(stateStmtDml)
+	dml := NewDmlStatementContext(p, stmt, p.GetState())
+	p.EnterRule(dml, stateDmlStatement, MySqlParserRULE_dmlStatement)
+	defer p.ExitRule()
+	defer func() {
+		if err := recover(); err != nil {
+			v, ok := err.(antlr.RecognitionException)
+			if !ok {
+				panic(err)
+			}
+			dml.SetException(v)
+			p.GetErrorHandler().ReportError(p, v)
+			p.GetErrorHandler().Recover(p, v)
+		}
+	}()
+
+	// CREATE VIEW 中 withClause 所在的位置，其后是 selectStatement
+	p.SetState(stateViewWith)
+	p.WithClause()
+	switch p.GetTokenStream().LA(1) {
+	case MySqlParserUPDATE:
+		p.SetState(stateDmlUpdate)
+		p.UpdateStatement()
+	case MySqlParserDELETE:
+		p.SetState(stateDmlDelete)
+		p.DeleteStatement()
+	default:
+		p.SetState(stateDmlSelect)
+		p.SelectStatement()
+	}
+}
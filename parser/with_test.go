@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	cases := []struct {
+		sql    string
+		stmt   string
+		tables []string
+		with   []WithInfo
+	}{
+		{
+			sql:    "WITH a AS (SELECT id FROM t WHERE x = 1) SELECT a.id FROM a JOIN u ON u.id = a.id",
+			stmt:   StatementSelect,
+			tables: []string{"t", "u"},
+			with: []WithInfo{
+				{Name: "a", Columns: []string{}, Content: "SELECT id FROM t WHERE x = 1"},
+			},
+		},
+		{
+			sql:    "WITH a AS (SELECT id FROM t), b (k) AS (SELECT k FROM s) SELECT * FROM a JOIN b ON a.id = b.k",
+			stmt:   StatementSelect,
+			tables: []string{"t", "s"},
+			with: []WithInfo{
+				{Name: "a", Columns: []string{}, Content: "SELECT id FROM t"},
+				{Name: "b", Columns: []string{"k"}, Content: "SELECT k FROM s"},
+			},
+		},
+		{
+			sql:    "WITH RECURSIVE r (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r WHERE n < 5) SELECT n FROM r",
+			stmt:   StatementSelect,
+			tables: []string{},
+			with: []WithInfo{
+				{Name: "r", Columns: []string{"n"}, Content: "SELECT 1 UNION ALL SELECT n + 1 FROM r WHERE n < 5", Recursive: true},
+			},
+		},
+		{
+			sql:    "WITH a AS (SELECT id FROM t) UPDATE u SET x = 1 WHERE id IN (SELECT id FROM a)",
+			stmt:   StatementUpdate,
+			tables: []string{"t", "u"},
+			with: []WithInfo{
+				{Name: "a", Columns: []string{}, Content: "SELECT id FROM t"},
+			},
+		},
+		{
+			sql:    "WITH a AS (SELECT id FROM t) DELETE FROM u WHERE id IN (SELECT id FROM a)",
+			stmt:   StatementDelete,
+			tables: []string{"t", "u"},
+			with: []WithInfo{
+				{Name: "a", Columns: []string{}, Content: "SELECT id FROM t"},
+			},
+		},
+		{
+			sql:    "CREATE VIEW v AS WITH a AS (SELECT id FROM t) SELECT id FROM a",
+			stmt:   StatementCreateView,
+			tables: []string{"t"},
+			with: []WithInfo{
+				{Name: "a", Columns: []string{}, Content: "SELECT id FROM t"},
+			},
+		},
+	}
+	for _, c := range cases {
+		result := mustParse(t, c.sql)
+		if result.StatementType != c.stmt {
+			t.Errorf("%q: StatementType = %q, want %q", c.sql, result.StatementType, c.stmt)
+		}
+		if !reflect.DeepEqual(result.Tables, c.tables) {
+			t.Errorf("%q: Tables = %v, want %v", c.sql, result.Tables, c.tables)
+		}
+		if !reflect.DeepEqual(result.With, c.with) {
+			t.Errorf("%q: With = %+v, want %+v", c.sql, result.With, c.with)
+		}
+	}
+}
+
+func TestWithMultiStatement(t *testing.T) {
+	_, err := ParseSQL("WITH a AS (SELECT 1) SELECT * FROM a; SELECT 2;")
+	if err != nil {
+		t.Fatalf("ParseSQL: %v", err)
+	}
+	if _, err := ParseSQL("WITH a AS (SELECT 1) SELEC * FROM a"); err == nil {
+		t.Error("ParseSQL: want syntax error")
+	}
+}
+
+func TestWithClauses(t *testing.T) {
+	list, err := ParseTokens("WITH a AS (SELECT id FROM t WHERE x = 1) SELECT id FROM a WHERE id > 2")
+	if err != nil {
+		t.Fatalf("ParseTokens: %v", err)
+	}
+	var outer []string
+	for _, c := range list.Clauses {
+		if c.Depth == 0 {
+			outer = append(outer, c.Name)
+		}
+	}
+	want := []string{ClauseSelect, ClauseFrom, ClauseWhere}
+	if !reflect.DeepEqual(outer, want) {
+		t.Errorf("outer clauses = %v, want %v", outer, want)
+	}
+	where, ok := list.Clause(ClauseWhere)
+	if !ok || list.Tokens[where.Start].Text != "WHERE" || list.Tokens[where.Stop].Text != "2" {
+		t.Errorf("Clause(WHERE) = %+v, %v", where, ok)
+	}
+}
+
+// generatedStates 返回生成代码中规则函数 fn 在 call 之前设置的状态号，按出现顺序
+func generatedStates(t *testing.T, src, fn, call string) []int {
+	t.Helper()
+	start := strings.Index(src, "func (p *MySqlParser) "+fn+"() ")
+	if start < 0 {
+		t.Fatalf("no generated function %s", fn)
+	}
+	body := src[start:]
+	if end := strings.Index(body[1:], "\nfunc "); end >= 0 {
+		body = body[:end+1]
+	}
+	var states []int
+	re := regexp.MustCompile(`p\.SetState\((\d+)\)\s*` + regexp.QuoteMeta(call))
+	for _, m := range re.FindAllStringSubmatch(body, -1) {
+		n, _ := strconv.Atoi(m[1])
+		states = append(states, n)
+	}
+	return states
+}
+
+// TestParseRootStates 核对 parseRoot 中使用的状态号，语法重新生成后状态号变化时失败
+func TestParseRootStates(t *testing.T) {
+	data, err := os.ReadFile("mysql_parser.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	last := func(states []int) int {
+		if len(states) == 0 {
+			return -1
+		}
+		return states[len(states)-1]
+	}
+	cases := []struct {
+		name  string
+		state int
+		got   int
+	}{
+		{"root -> sqlStatements", stateRootStmts, last(generatedStates(t, src, "Root", "p.SqlStatements()"))},
+		{"root EOF", stateRootEOF, last(generatedStates(t, src, "Root", "p.Match(MySqlParserEOF)"))},
+		{"sqlStatements -> sqlStatement", stateStmtsLast, last(generatedStates(t, src, "SqlStatements", "p.SqlStatement()"))},
+		{"sqlStatements SEMI", stateStmtsSemi, last(generatedStates(t, src, "SqlStatements", "p.Match(MySqlParserSEMI)"))},
+		{"sqlStatement -> dmlStatement", stateStmtDml, last(generatedStates(t, src, "SqlStatement", "p.DmlStatement()"))},
+		{"createView -> withClause", stateViewWith, last(generatedStates(t, src, "CreateView", "p.WithClause()"))},
+		{"dmlStatement -> selectStatement", stateDmlSelect, last(generatedStates(t, src, "DmlStatement", "p.SelectStatement()"))},
+		{"dmlStatement -> updateStatement", stateDmlUpdate, last(generatedStates(t, src, "DmlStatement", "p.UpdateStatement()"))},
+		{"dmlStatement -> deleteStatement", stateDmlDelete, last(generatedStates(t, src, "DmlStatement", "p.DeleteStatement()"))},
+	}
+	for _, c := range cases {
+		if c.got != c.state {
+			t.Errorf("%s: generated state %d, parseRoot uses %d", c.name, c.got, c.state)
+		}
+	}
+
+	// 规则的起始状态是生成代码中 EnterRule 的第二个参数
+	for rule, state := range map[string]int{
+		"root":          stateRoot,
+		"sqlStatements": stateSqlStatements,
+		"sqlStatement":  stateSqlStatement,
+		"dmlStatement":  stateDmlStatement,
+	} {
+		m := regexp.MustCompile(`p\.EnterRule\(localctx, (\d+), MySqlParserRULE_` + rule + `\)`).FindStringSubmatch(src)
+		if m == nil || m[1] != strconv.Itoa(state) {
+			t.Errorf("%s start state: generated %v, parseRoot uses %d", rule, m, state)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package pool
 
 import (
+	"errors"
 	"sync"
+	"time"
 )
 
+// ErrClosed 重复关闭工作池
+var ErrClosed = errors.New("pool already closed")
+
 // init
 func init() {
 }
@@ -13,27 +18,66 @@ func init() {
 type WaitGroup struct {
 	workChan chan int
 	wg       sync.WaitGroup
+
+	mu        sync.Mutex
+	closed    bool
+	active    int
+	submitted uint64
+	completed uint64
+	panics    uint64
+	timers    map[*time.Timer]struct{} // SubmitAfter 提交的尚未到期的任务
+	scheduled *sync.Cond               // timers 减少时通知 Drain，与 mu 配合使用
+}
+
+// PoolStats 工作池的状态快照
+type PoolStats struct {
+	Capacity       int    // 最大并发数
+	Active         int    // 正在执行的任务数
+	Available      int    // 空闲的并发数
+	TotalSubmitted uint64 // 累计提交的任务数
+	TotalCompleted uint64 // 累计完成的任务数（含 panic 的）
+	Panics         uint64 // Go 提交的任务中发生 panic 的次数
+	Scheduled      int    // SubmitAfter 提交的尚未到期的任务数
 }
 
 // NewPool 生成一个工作池, coreNum 限制
 
 func NewPool(coreNum int) *WaitGroup {
 	ch := make(chan int, coreNum)
-	return &WaitGroup{
+	p := &WaitGroup{
 		workChan: ch,
 		wg:       sync.WaitGroup{},
 	}
+	p.scheduled = sync.NewCond(&p.mu)
+	return p
 }
 
 // Add 添加
 
 func (p *WaitGroup) Add(num int) {
 	for i := 0; i < num; i++ {
-		p.workChan <- i
+		// 先在锁内登记到 wg，保证 Close 一定会等到这个任务
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			panic("pool: Add called after Close")
+		}
 		p.wg.Add(1)
+		p.mu.Unlock()
+
+		p.acquire(i)
 	}
 }
 
+// acquire 占用一个并发数，调用前需要已经登记到 wg
+func (p *WaitGroup) acquire(i int) {
+	p.workChan <- i
+	p.mu.Lock()
+	p.active++
+	p.submitted++
+	p.mu.Unlock()
+}
+
 // Done
 
 func (p *WaitGroup) Done() {
@@ -44,11 +88,124 @@ LOOP:
 			break LOOP
 		}
 	}
+	p.mu.Lock()
+	p.active--
+	p.completed++
+	p.mu.Unlock()
 	p.wg.Done()
 }
 
+// Go 占用一个并发数异步执行 fn，fn 中的 panic 会被恢复并计入 Panics
+
+func (p *WaitGroup) Go(fn func()) {
+	p.Add(1)
+	p.spawn(fn)
+}
+
+// spawn 在已占用的并发数中异步执行 fn
+func (p *WaitGroup) spawn(fn func()) {
+	go func() {
+		defer p.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				p.mu.Lock()
+				p.panics++
+				p.mu.Unlock()
+			}
+		}()
+		fn()
+	}()
+}
+
+// SubmitAfter 在 d 之后按 Go 的方式执行 task，到期时同样受最大并发数限制，需要时排队等待。
+// 未到期的任务不计入 Wait，Close 时直接取消；Close 之后调用会 panic
+
+func (p *WaitGroup) SubmitAfter(d time.Duration, task func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		panic("pool: SubmitAfter called after Close")
+	}
+	if p.timers == nil {
+		p.timers = map[*time.Timer]struct{}{}
+	}
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		// 与 Close 互斥：要么在 Close 之前登记到 wg，要么看到已关闭而放弃
+		p.mu.Lock()
+		delete(p.timers, t)
+		p.scheduled.Broadcast()
+		if p.closed {
+			p.mu.Unlock()
+			return
+		}
+		p.wg.Add(1)
+		p.mu.Unlock()
+
+		p.acquire(0)
+		p.spawn(task)
+	})
+	p.timers[t] = struct{}{}
+}
+
 // Wait 等待
 
 func (p *WaitGroup) Wait() {
 	p.wg.Wait()
 }
+
+// Drain 等待全部任务完成：先等尚未到期的 SubmitAfter 任务到期并登记，再按 Wait 等待所有已登记的任务。
+// Wait 不等待未到期的延迟任务，Close 会直接取消它们；需要延迟任务也执行完时先 Drain 再 Close。
+// Drain 不关闭工作池
+
+func (p *WaitGroup) Drain() {
+	p.mu.Lock()
+	for len(p.timers) > 0 {
+		p.scheduled.Wait()
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// Stats 返回工作池的状态快照，各项数值在同一把锁下读取，彼此一致
+
+func (p *WaitGroup) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{
+		Capacity:       cap(p.workChan),
+		Active:         p.active,
+		Available:      cap(p.workChan) - p.active,
+		TotalSubmitted: p.submitted,
+		TotalCompleted: p.completed,
+		Panics:         p.panics,
+		Scheduled:      len(p.timers),
+	}
+}
+
+// Close 关闭工作池并释放内部资源，重复调用返回 ErrClosed。按顺序：
+//   - 不再接受新任务，之后调用 Add/Go/SubmitAfter 会 panic；
+//   - 取消尚未到期的 SubmitAfter 任务，这些任务不会执行（已到期的按已登记的任务处理）；
+//   - 与 Wait 相同，等待所有已登记的任务完成，包括正在执行的和已调用 Add 但仍在排队等待并发数的；
+//   - 关闭内部通道。
+//
+// Close 已包含 Wait；需要等未到期的延迟任务也执行完时先调用 Drain
+
+func (p *WaitGroup) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	p.closed = true
+	for t := range p.timers {
+		t.Stop()
+	}
+	p.timers = nil
+	p.scheduled.Broadcast()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	close(p.workChan)
+	return nil
+}
@@ -0,0 +1,139 @@
+package pool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGoLimitsConcurrency(t *testing.T) {
+	cases := []struct {
+		capacity int
+		tasks    int
+	}{
+		{1, 5},
+		{3, 20},
+		{8, 8},
+	}
+	for _, c := range cases {
+		p := NewPool(c.capacity)
+		var running, peak, done int32
+		for i := 0; i < c.tasks; i++ {
+			p.Go(func() {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&done, 1)
+			})
+		}
+		p.Wait()
+		if int(peak) > c.capacity {
+			t.Errorf("capacity %d: peak concurrency %d", c.capacity, peak)
+		}
+		if int(done) != c.tasks {
+			t.Errorf("capacity %d: %d of %d tasks done", c.capacity, done, c.tasks)
+		}
+		stats := p.Stats()
+		if stats.TotalSubmitted != uint64(c.tasks) || stats.TotalCompleted != uint64(c.tasks) || stats.Active != 0 || stats.Available != c.capacity {
+			t.Errorf("capacity %d: Stats = %+v", c.capacity, stats)
+		}
+	}
+}
+
+func TestGoRecoversPanics(t *testing.T) {
+	p := NewPool(2)
+	p.Go(func() { panic("boom") })
+	p.Go(func() {})
+	p.Wait()
+	if stats := p.Stats(); stats.Panics != 1 || stats.TotalCompleted != 2 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestSubmitAfter(t *testing.T) {
+	p := NewPool(1)
+	var ran int32
+	start := time.Now()
+	p.SubmitAfter(20*time.Millisecond, func() { atomic.AddInt32(&ran, 1) })
+	if stats := p.Stats(); stats.Scheduled != 1 {
+		t.Errorf("Scheduled = %d, want 1", stats.Scheduled)
+	}
+	p.Drain()
+	if atomic.LoadInt32(&ran) != 1 {
+		t.Fatalf("task did not run")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("task ran after %v", elapsed)
+	}
+	if stats := p.Stats(); stats.Scheduled != 0 || stats.TotalCompleted != 1 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestClose(t *testing.T) {
+	cases := []struct {
+		name     string
+		drain    bool // Close 之前先 Drain
+		wantRuns int32
+	}{
+		{name: "Close 等待已提交的任务，取消未到期的延迟任务", wantRuns: 2},
+		{name: "先 Drain 再 Close 时延迟任务也会执行", drain: true, wantRuns: 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewPool(1)
+			var runs int32
+			task := func() {
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&runs, 1)
+			}
+			p.Go(task)
+			p.Go(task) // 排队等待并发数
+			p.SubmitAfter(30*time.Millisecond, task)
+			if c.drain {
+				p.Drain()
+			}
+			if err := p.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := atomic.LoadInt32(&runs); got != c.wantRuns {
+				t.Errorf("ran %d tasks before Close returned, want %d", got, c.wantRuns)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if got := atomic.LoadInt32(&runs); got != c.wantRuns {
+				t.Errorf("ran %d tasks after Close, want %d", got, c.wantRuns)
+			}
+			if stats := p.Stats(); stats.Scheduled != 0 {
+				t.Errorf("Scheduled = %d after Close", stats.Scheduled)
+			}
+			if err := p.Close(); !errors.Is(err, ErrClosed) {
+				t.Errorf("second Close = %v, want ErrClosed", err)
+			}
+		})
+	}
+}
+
+func TestSubmitAfterClose(t *testing.T) {
+	for name, submit := range map[string]func(p *WaitGroup){
+		"Go":          func(p *WaitGroup) { p.Go(func() {}) },
+		"SubmitAfter": func(p *WaitGroup) { p.SubmitAfter(time.Millisecond, func() {}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := NewPool(1)
+			_ = p.Close()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s after Close did not panic", name)
+				}
+			}()
+			submit(p)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package xshell
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	ps "github.com/bhendo/go-powershell"
+)
+
+// exitMarker 命令执行结束后输出退出码的标记行
+const exitMarker = "__BUS_EXIT_CODE__:"
+
+// StderrTailLines ExitError 中保留的 stderr 末尾行数
+var StderrTailLines = 20
+
+// Options 单条命令的执行选项
+type Options struct {
+	Env map[string]string // 仅对本条命令生效的环境变量，执行结束后恢复原值
+	Dir string            // 命令的工作目录，为空时使用会话当前目录
+}
+
+// ExitError 命令以非 0 退出码结束
+type ExitError struct {
+	Cmd      string
+	ExitCode int
+	Stderr   string // stderr 的最后 StderrTailLines 行
+}
+
+func (e *ExitError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("command exited with code %d: %v", e.ExitCode, e.Cmd)
+	}
+	return fmt.Sprintf("command exited with code %d: %v\n%v", e.ExitCode, e.Cmd, e.Stderr)
+}
+
+// Session 一个 powershell 会话，在 ps.Shell 的基础上支持退出码、环境变量和工作目录
+type Session struct {
+	ps.Shell
+}
+
+// NewSession 开启一个本地 powershell 会话
+func NewSession() (*Session, error) {
+	shell, err := Powershell()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Shell: shell}, nil
+}
+
+// ExecuteDetailed 执行一条命令并返回退出码。
+//
+// 环境变量和工作目录只在本条命令内生效，由 powershell 在命令结束后恢复，不会影响当前进程。
+// 退出码取 $LASTEXITCODE，没有外部程序设置时按命令是否成功取 0 或 1。
+// stderr 有输出但退出码为 0 不视为错误，err 只表示命令没能正常执行完；
+// ctx 结束时立即返回 ctx.Err()，此时会话状态不确定，调用方应当 Exit 掉该会话。
+func (s *Session) ExecuteDetailed(ctx context.Context, cmd string, opts Options) (stdout, stderr string, exitCode int, err error) {
+	if err = ctx.Err(); err != nil {
+		return "", "", -1, err
+	}
+
+	type result struct {
+		stdout, stderr string
+		err            error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out, errOut, err := s.Shell.Execute(wrapCommand(cmd, opts))
+		done <- result{out, errOut, err}
+	}()
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return "", "", -1, ctx.Err()
+	case res = <-done:
+	}
+
+	stdout, exitCode, ok := splitExitCode(res.stdout)
+	if !ok {
+		if res.err != nil {
+			return stdout, res.stderr, -1, res.err
+		}
+		return stdout, res.stderr, -1, fmt.Errorf("exit code not found in output of: %v", cmd)
+	}
+	return stdout, res.stderr, exitCode, nil
+}
+
+// ExecuteChecked 执行命令，退出码非 0 时返回 *ExitError
+func (s *Session) ExecuteChecked(ctx context.Context, cmd string, opts Options) (stdout, stderr string, err error) {
+	stdout, stderr, exitCode, err := s.ExecuteDetailed(ctx, cmd, opts)
+	if err != nil {
+		return stdout, stderr, err
+	}
+	if exitCode != 0 {
+		return stdout, stderr, &ExitError{Cmd: cmd, ExitCode: exitCode, Stderr: tailLines(stderr, StderrTailLines)}
+	}
+	return stdout, stderr, nil
+}
+
+// wrapCommand 把命令包装成一行 powershell 脚本：设置环境变量和目录、执行、恢复、输出退出码
+func wrapCommand(cmd string, opts Options) string {
+	var b strings.Builder
+	b.WriteString("$global:LASTEXITCODE = 0; $__busEnv = @{}; ")
+
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "$__busEnv[%v] = [Environment]::GetEnvironmentVariable(%v); [Environment]::SetEnvironmentVariable(%v, %v); ",
+			quote(k), quote(k), quote(k), quote(opts.Env[k]))
+	}
+	if opts.Dir != "" {
+		fmt.Fprintf(&b, "Push-Location -LiteralPath %v; ", quote(opts.Dir))
+	}
+
+	cmd = strings.ReplaceAll(strings.ReplaceAll(cmd, "\r\n", "; "), "\n", "; ")
+	fmt.Fprintf(&b, "try { %v; $__busOk = $? } catch { $__busOk = $false; [Console]::Error.WriteLine($_) } finally { ", cmd)
+	if opts.Dir != "" {
+		b.WriteString("Pop-Location; ")
+	}
+	b.WriteString("foreach ($__busKey in $__busEnv.Keys) { [Environment]::SetEnvironmentVariable($__busKey, $__busEnv[$__busKey]) } }; ")
+	b.WriteString("if ($global:LASTEXITCODE) { $__busCode = $global:LASTEXITCODE } elseif ($__busOk) { $__busCode = 0 } else { $__busCode = 1 }; ")
+	fmt.Fprintf(&b, "echo ('%v' + $__busCode)", exitMarker)
+	return b.String()
+}
+
+// splitExitCode 从输出中取出退出码标记行，返回去掉标记后的输出
+func splitExitCode(out string) (string, int, bool) {
+	idx := strings.LastIndex(out, exitMarker)
+	if idx < 0 {
+		return out, -1, false
+	}
+	line := out[idx+len(exitMarker):]
+	if end := strings.IndexAny(line, "\r\n"); end >= 0 {
+		line = line[:end]
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		return out, -1, false
+	}
+	return out[:idx], code, true
+}
+
+// quote powershell 单引号字符串
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// tailLines 返回 s 的最后 n 行
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\r\n"), "\n")
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
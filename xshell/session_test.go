@@ -0,0 +1,160 @@
+package xshell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeShell 代替 powershell 进程，记录执行的脚本并返回预设的输出
+type fakeShell struct {
+	scripts        []string
+	stdout, stderr string
+	err            error
+	block          chan struct{} // 非 nil 时 Execute 阻塞到关闭
+}
+
+func (f *fakeShell) Execute(cmd string) (string, string, error) {
+	f.scripts = append(f.scripts, cmd)
+	if f.block != nil {
+		<-f.block
+	}
+	return f.stdout, f.stderr, f.err
+}
+
+func (f *fakeShell) Exit() {}
+
+func TestWrapCommand(t *testing.T) {
+	script := wrapCommand("Write-Output 'a'\r\nGet-Item x\nexit 3", Options{
+		Env: map[string]string{"B": "2", "A": "it's"},
+		Dir: `C:\my dir`,
+	})
+	for _, want := range []string{
+		"$global:LASTEXITCODE = 0; ",
+		"[Environment]::SetEnvironmentVariable('A', 'it''s'); ",
+		"Push-Location -LiteralPath 'C:\\my dir'; ",
+		"try { Write-Output 'a'; Get-Item x; exit 3; $__busOk = $? }",
+		"finally { Pop-Location; foreach",
+		"echo ('" + exitMarker + "' + $__busCode)",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script does not contain %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "\n") {
+		t.Errorf("script is not a single line:\n%s", script)
+	}
+	if strings.Index(script, "'A'") > strings.Index(script, "'B'") {
+		t.Errorf("environment variables not sorted:\n%s", script)
+	}
+
+	plain := wrapCommand("dir", Options{})
+	if strings.Contains(plain, "Push-Location") || strings.Contains(plain, "SetEnvironmentVariable('") {
+		t.Errorf("script without options = %s", plain)
+	}
+}
+
+func TestSplitExitCode(t *testing.T) {
+	cases := []struct {
+		out    string
+		stdout string
+		code   int
+		ok     bool
+	}{
+		{"hello\r\n" + exitMarker + "0\r\n", "hello\r\n", 0, true},
+		{exitMarker + " 3", "", 3, true},
+		{"a " + exitMarker + "1\n" + exitMarker + "2\n", "a " + exitMarker + "1\n", 2, true},
+		{"no marker", "no marker", -1, false},
+		{exitMarker + "x\n", exitMarker + "x\n", -1, false},
+	}
+	for _, c := range cases {
+		stdout, code, ok := splitExitCode(c.out)
+		if stdout != c.stdout || code != c.code || ok != c.ok {
+			t.Errorf("splitExitCode(%q) = %q, %d, %v, want %q, %d, %v", c.out, stdout, code, ok, c.stdout, c.code, c.ok)
+		}
+	}
+}
+
+func TestExecuteDetailed(t *testing.T) {
+	cases := []struct {
+		name   string
+		shell  fakeShell
+		stdout string
+		stderr string
+		code   int
+		err    bool
+	}{
+		{"success", fakeShell{stdout: "out\n" + exitMarker + "0\n"}, "out\n", "", 0, false},
+		{"exit code", fakeShell{stdout: exitMarker + "5\n", stderr: "boom\n"}, "", "boom\n", 5, false},
+		{"shell error", fakeShell{stdout: "partial", err: errors.New("pipe closed")}, "partial", "", -1, true},
+		{"no marker", fakeShell{stdout: "partial"}, "partial", "", -1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			shell := c.shell
+			s := &Session{Shell: &shell}
+			stdout, stderr, code, err := s.ExecuteDetailed(context.Background(), "cmd", Options{})
+			if stdout != c.stdout || stderr != c.stderr || code != c.code || (err != nil) != c.err {
+				t.Errorf("ExecuteDetailed = %q, %q, %d, %v", stdout, stderr, code, err)
+			}
+			if len(shell.scripts) != 1 || shell.scripts[0] != wrapCommand("cmd", Options{}) {
+				t.Errorf("scripts = %q", shell.scripts)
+			}
+		})
+	}
+}
+
+func TestExecuteDetailedContext(t *testing.T) {
+	shell := &fakeShell{block: make(chan struct{})}
+	defer close(shell.block)
+	s := &Session{Shell: shell}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := s.ExecuteDetailed(ctx, "cmd", Options{}); err != context.Canceled {
+		t.Errorf("canceled context: err = %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, code, err := s.ExecuteDetailed(ctx, "cmd", Options{}); err != context.DeadlineExceeded || code != -1 {
+		t.Errorf("timeout: code, err = %d, %v", code, err)
+	}
+}
+
+func TestExecuteChecked(t *testing.T) {
+	var stderr strings.Builder
+	for i := 1; i <= StderrTailLines+5; i++ {
+		fmt.Fprintf(&stderr, "line %d\r\n", i)
+	}
+	s := &Session{Shell: &fakeShell{stdout: "out\n" + exitMarker + "2\n", stderr: stderr.String()}}
+	stdout, errOut, err := s.ExecuteChecked(context.Background(), "build", Options{})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err = %v, want *ExitError", err)
+	}
+	if stdout != "out\n" || errOut != stderr.String() {
+		t.Errorf("stdout, stderr = %q, %q", stdout, errOut)
+	}
+	if exitErr.Cmd != "build" || exitErr.ExitCode != 2 {
+		t.Errorf("ExitError = %+v", exitErr)
+	}
+	lines := strings.Split(exitErr.Stderr, "\n")
+	if len(lines) != StderrTailLines || !strings.HasPrefix(lines[0], "line 6") || !strings.HasPrefix(lines[len(lines)-1], "line 25") {
+		t.Errorf("Stderr = %q, want the last %d lines", exitErr.Stderr, StderrTailLines)
+	}
+	if msg := exitErr.Error(); !strings.HasPrefix(msg, "command exited with code 2: build\n") {
+		t.Errorf("Error() = %q", msg)
+	}
+	if msg := (&ExitError{Cmd: "x", ExitCode: 1}).Error(); msg != "command exited with code 1: x" {
+		t.Errorf("Error() without stderr = %q", msg)
+	}
+
+	s = &Session{Shell: &fakeShell{stdout: exitMarker + "0\n", stderr: "warning\n"}}
+	if _, _, err := s.ExecuteChecked(context.Background(), "ok", Options{}); err != nil {
+		t.Errorf("exit code 0 with stderr: err = %v", err)
+	}
+}